@@ -5,7 +5,7 @@ use super::commands::{
     CleanCommandArgs, CompletionsCommandArgs, CyclesCommandArgs, DepsCommandArgs, DupesCommandArgs,
     ExportsCommandArgs, GenerateCommandArgs, GlossaryCommandArgs, InitCommandArgs,
     LookupCommandArgs, LsCommandArgs, OutlineCommandArgs, ReadCommandArgs, SearchCommandArgs,
-    SimilarCommandArgs, ValidateCommandArgs, WatchCommandArgs,
+    SimilarCommandArgs, ValidateCommandArgs, VersionCommandArgs, WatchCommandArgs,
 };
 use super::generated_help;
 
@@ -34,6 +34,7 @@ pub enum Commands {
   <dim>$</dim> <bold>fmm generate -n</bold>                     <dim># Dry run — preview without writing</dim>
   <dim>$</dim> <bold>fmm generate --force</bold>                <dim># Regenerate all, even if unchanged</dim>
   <dim>$</dim> <bold>fmm generate && fmm validate</bold>        <dim># Generate then verify</dim>
+  <dim>$</dim> <bold>fmm generate --json</bold>                 <dim># Indexed count and parse errors as JSON</dim>
 
 <bold><underline>Notes</underline></bold>
   Indexes new files and updates stale entries in a single pass.
@@ -158,6 +159,18 @@ pub enum Commands {
     )]
     Status,
 
+    /// Print the version, supported languages, output formats and features
+    #[command(
+        long_about = "Print the fmm version with the languages it parses, the output formats \
+            of 'fmm outline', and a list of feature names. Scripts can check for a \
+            feature name before relying on it; names are only ever added.",
+        after_long_help = cstr!(
+            r#"<bold><underline>Examples</underline></bold>
+  <dim>$</dim> <bold>fmm version</bold>                        <dim># Human-readable summary</dim>
+  <dim>$</dim> <bold>fmm version --json | jq .features</bold>  <dim># Feature names for scripts</dim>"#),
+    )]
+    Version(VersionCommandArgs),
+
     /// Query the index — O(1) export lookup, dependency graphs, LOC filters
     #[command(
         long_about = generated_help::SEARCH_ABOUT,
@@ -316,7 +329,25 @@ pub enum Commands {
             r#"<bold><underline>Examples</underline></bold>
   <dim>$</dim> <bold>fmm outline crates/fmm-core/src/parser/mod.rs</bold> <dim># Symbols + signature/visibility/kind</dim>
   <dim>$</dim> <bold>fmm outline crates/fmm-core/src/parser/mod.rs --include-private</bold> <dim># Include private members</dim>
-  <dim>$</dim> <bold>fmm outline crates/fmm-core/src/parser/mod.rs --json</bold> <dim># JSON output</dim>"#),
+  <dim>$</dim> <bold>fmm outline crates/fmm-core/src/parser/mod.rs --json</bold> <dim># JSON output</dim>
+  <dim>$</dim> <bold>fmm outline crates/fmm-core/src/parser/mod.rs --signature-only</bold> <dim># One signature per line</dim>
+  <dim>$</dim> <bold>fmm outline crates/fmm-core/src/parser/mod.rs --sort kind</bold> <dim># Group by kind, then name</dim>
+  <dim>$</dim> <bold>fmm outline crates/fmm-core/src/parser/mod.rs --tree</bold> <dim># Members as tree branches</dim>
+  <dim>$</dim> <bold>fmm outline main.go --qualify-imports</bold> <dim># redis.Client as github.com/redis/go-redis/v9.Client</dim>
+  <dim>$</dim> <bold>fmm outline store.go --expand-embeds</bold> <dim># Interface methods promoted from embeds</dim>
+  <dim>$</dim> <bold>fmm outline main.go --json --annotate-cmd ./lookup.sh</bold> <dim># Merge per-symbol metadata from a script</dim>
+  <dim>$</dim> <bold>fmm outline main.go --json --min-complexity 5</bold> <dim># Only functions with complexity 5 or more</dim>
+  <dim>$</dim> <bold>fmm outline main.go --json --markers --marker-tag XXX</bold> <dim># TODO/FIXME/HACK/XXX comments per function</dim>
+  <dim>$</dim> <bold>fmm outline main.go --json --eval-consts</bold> <dim># Constants with their folded values</dim>
+  <dim>$</dim> <bold>fmm outline main.go --json --only-deprecated</bold> <dim># Deprecated API left to migrate</dim>
+  <dim>$</dim> <bold>fmm outline main.go --callgraph | dot -Tsvg > calls.svg</bold> <dim># Draw calls between functions</dim>
+  <dim>$</dim> <bold>fmm outline main.go --undocumented --max-undocumented 3</bold> <dim># Fail on more than 3 undocumented exports</dim>
+  <dim>$</dim> <bold>fmm outline main.go --receiver-report</bold> <dim># Types with mixed receiver styles or names</dim>
+  <dim>$</dim> <bold>fmm outline main.go --imports-only</bold> <dim># Imports by kind, unused ones marked</dim>
+  <dim>$</dim> <bold>fmm outline main.go --layout</bold> <dim># Struct sizes, padding and a tighter field order</dim>
+  <dim>$</dim> <bold>fmm outline main.go --error-report</bold> <dim># Functions returning error, and where</dim>
+  <dim>$</dim> <bold>fmm outline main.go --shadow-report</bold> <dim># Locals hiding package-level names</dim>
+  <dim>$</dim> <bold>fmm outline - --lang go < main.go</bold> <dim># Outline source piped through stdin</dim>"#),
     )]
     Outline(OutlineCommandArgs),
 
@@ -356,6 +387,9 @@ pub enum Commands {
   <dim>$</dim> <bold>fmm exports 'Parser$'</bold>                    <dim># Regex: exports ending in "Parser"</dim>
   <dim>$</dim> <bold>fmm exports '^[A-Z]'</bold>                     <dim># Regex: PascalCase exports only</dim>
   <dim>$</dim> <bold>fmm exports Parser --dir crates/fmm-core/src/parser</bold> <dim># Scoped to directory</dim>
+  <dim>$</dim> <bold>fmm exports --kind struct --kind trait</bold>   <dim># Only structs and traits</dim>
+  <dim>$</dim> <bold>fmm exports '^New' --name-exclude 'Mock'</bold> <dim># Constructors, minus mocks</dim>
+  <dim>$</dim> <bold>fmm exports --api-hash</bold>                   <dim># SHA-256 of each Go package's exported API</dim>
   <dim>$</dim> <bold>fmm exports Parser --limit 50 --offset 50</bold> <dim># Pagination</dim>
   <dim>$</dim> <bold>fmm exports Parser --json</bold>                 <dim># JSON output</dim>"#),
     )]
@@ -0,0 +1,262 @@
+use std::collections::BTreeMap;
+use std::path::Path;
+
+use anyhow::Result;
+use colored::Colorize;
+use fmm_core::config::Config;
+use fmm_core::manifest::{FileEntry, Manifest};
+use sha2::{Digest, Sha256};
+
+use super::exports::path_matches_directory;
+use crate::cli::sidecar::read_go_package;
+
+/// A Go package: its directory under the project root and its name.
+type PackageKey = (String, String);
+
+#[derive(serde::Serialize)]
+struct ApiHashJson {
+    package: String,
+    dir: String,
+    hash: String,
+}
+
+/// Print `--api-hash`: one SHA-256 per Go package under `directory`, as
+/// `package (dir): hash`. The hash covers the exported symbols of the
+/// package's non-test files, so it changes when one is added, removed,
+/// renamed or given a different signature, and not when a file is reformatted,
+/// re-documented or split.
+pub(super) fn print_api_hashes(
+    root: &Path,
+    manifest: &Manifest,
+    config: &Config,
+    directory: Option<&str>,
+    json_output: bool,
+) -> Result<()> {
+    let hashes = api_hashes(root, manifest, config, directory);
+    if json_output {
+        let json: Vec<ApiHashJson> = hashes
+            .into_iter()
+            .map(|((dir, package), hash)| ApiHashJson { package, dir, hash })
+            .collect();
+        println!("{}", serde_json::to_string_pretty(&json)?);
+    } else if hashes.is_empty() {
+        println!("{} No Go packages indexed", "!".yellow());
+    } else {
+        for ((dir, package), hash) in &hashes {
+            println!("{package} ({dir}): {hash}");
+        }
+    }
+    Ok(())
+}
+
+/// Hex SHA-256 of each package's sorted API lines. Packages are told apart by
+/// directory and package clause, as `fmm generate --stats` does, and test
+/// files are left out: `go test` builds them, importers never see them.
+fn api_hashes(
+    root: &Path,
+    manifest: &Manifest,
+    config: &Config,
+    directory: Option<&str>,
+) -> BTreeMap<PackageKey, String> {
+    let mut surfaces: BTreeMap<PackageKey, Vec<String>> = BTreeMap::new();
+    for (file, entry) in &manifest.files {
+        if !path_matches_directory(file, directory) || config.is_test_file(file) {
+            continue;
+        }
+        let Some(package) = read_go_package(&root.join(file)) else {
+            continue;
+        };
+        surfaces
+            .entry((package_dir(file), package))
+            .or_default()
+            .extend(api_lines(entry));
+    }
+    surfaces
+        .into_iter()
+        .map(|(key, mut lines)| {
+            lines.sort();
+            (key, hash_lines(&lines))
+        })
+        .collect()
+}
+
+/// `kind name signature` for each exported symbol of `entry`, methods and
+/// struct fields under their dotted `Type.name`. Positions and doc comments
+/// are not part of a line.
+fn api_lines(entry: &FileEntry) -> impl Iterator<Item = String> + '_ {
+    let exports = entry
+        .exports
+        .iter()
+        .map(|name| (name, entry.export_metadata.get(name)));
+    let members = entry
+        .method_metadata
+        .iter()
+        .map(|(name, metadata)| (name, Some(metadata)));
+    exports
+        .chain(members)
+        .filter(|(_, metadata)| {
+            !matches!(
+                metadata.and_then(|metadata| metadata.visibility.as_deref()),
+                Some("private" | "non_exported")
+            )
+        })
+        .map(|(name, metadata)| {
+            let metadata = metadata.cloned().unwrap_or_default();
+            let kind = metadata.declaration_kind.as_deref().unwrap_or("other");
+            let signature = metadata.signature.as_deref().unwrap_or_default();
+            format!("{kind} {name} {}", normalize_signature(signature))
+        })
+}
+
+fn hash_lines(lines: &[String]) -> String {
+    let mut hasher = Sha256::new();
+    for line in lines {
+        hasher.update(line.as_bytes());
+        hasher.update(b"\n");
+    }
+    format!("{:x}", hasher.finalize())
+}
+
+/// `signature` without comments, without the trailing comma of a wrapped
+/// list, and with whitespace kept only as one space between two words, so
+/// `Run(\n\tctx context.Context,\n\topts Options,\n)` and
+/// `Run(ctx context.Context, opts Options)` read the same. String and rune
+/// literals are copied as written.
+fn normalize_signature(signature: &str) -> String {
+    let mut normalized = String::with_capacity(signature.len());
+    let mut chars = signature.chars().peekable();
+    let mut spaced = false;
+    while let Some(c) = chars.next() {
+        match c {
+            '/' if chars.peek() == Some(&'/') => {
+                for c in chars.by_ref() {
+                    if c == '\n' {
+                        break;
+                    }
+                }
+                spaced = true;
+            }
+            '/' if chars.peek() == Some(&'*') => {
+                chars.next();
+                let mut previous = '\0';
+                for c in chars.by_ref() {
+                    if previous == '*' && c == '/' {
+                        break;
+                    }
+                    previous = c;
+                }
+                spaced = true;
+            }
+            c if c.is_whitespace() => spaced = true,
+            c => {
+                if spaced && is_word(c) && normalized.chars().next_back().is_some_and(is_word) {
+                    normalized.push(' ');
+                }
+                spaced = false;
+                if matches!(c, ')' | ']' | '}') && normalized.ends_with(',') {
+                    normalized.pop();
+                }
+                normalized.push(c);
+                if matches!(c, '"' | '\'' | '`') {
+                    copy_literal(&mut chars, c, &mut normalized);
+                }
+            }
+        }
+    }
+    normalized
+}
+
+/// Copy the rest of a literal opened by `quote`, through its closing quote.
+/// Raw strings have no escapes.
+fn copy_literal(chars: &mut impl Iterator<Item = char>, quote: char, normalized: &mut String) {
+    let mut escaped = false;
+    for c in chars {
+        normalized.push(c);
+        if escaped {
+            escaped = false;
+        } else if c == '\\' && quote != '`' {
+            escaped = true;
+        } else if c == quote {
+            break;
+        }
+    }
+}
+
+fn is_word(c: char) -> bool {
+    c.is_alphanumeric() || c == '_'
+}
+
+/// `file`'s directory, `.` at the project root.
+fn package_dir(file: &str) -> String {
+    file.rsplit_once('/')
+        .map_or(".", |(dir, _)| dir)
+        .to_string()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use fmm_core::parser::{DeclarationKind, ExportEntry, Metadata, SymbolVisibility};
+
+    fn entry(name: &str, signature: &str, visibility: SymbolVisibility) -> ExportEntry {
+        let mut entry = ExportEntry::new(name.to_string(), 1, 1);
+        entry.signature = Some(signature.to_string());
+        entry.declaration_kind = Some(DeclarationKind::Fn);
+        entry.visibility = Some(visibility);
+        entry
+    }
+
+    fn hash(exports: Vec<ExportEntry>) -> String {
+        let file = FileEntry::from(Metadata {
+            exports,
+            ..Default::default()
+        });
+        let mut lines: Vec<String> = api_lines(&file).collect();
+        lines.sort();
+        hash_lines(&lines)
+    }
+
+    #[test]
+    fn normalizing_drops_formatting_and_comments_but_not_literals() {
+        assert_eq!(
+            normalize_signature(
+                "func Run(ctx  context.Context, // deadline\n\topts /* all */ Options,\n) error"
+            ),
+            "func Run(ctx context.Context,opts Options)error"
+        );
+        assert_eq!(
+            normalize_signature("const Greeting = \"hello,  world // not a comment\""),
+            "const Greeting=\"hello,  world // not a comment\""
+        );
+    }
+
+    #[test]
+    fn hash_ignores_order_and_formatting_but_not_the_api() {
+        let serve = entry(
+            "Serve",
+            "func Serve(addr string) error",
+            SymbolVisibility::Public,
+        );
+        let stop = entry("Stop", "func Stop()", SymbolVisibility::Public);
+        let baseline = hash(vec![serve.clone(), stop.clone()]);
+
+        let reflowed = entry(
+            "Serve",
+            "func Serve(\n\taddr string,\n) error",
+            SymbolVisibility::Public,
+        );
+        assert_eq!(hash(vec![stop.clone(), reflowed]), baseline);
+        let helper = entry("helper", "func helper()", SymbolVisibility::NonExported);
+        assert_eq!(hash(vec![serve.clone(), stop.clone(), helper]), baseline);
+
+        let renamed = entry("Halt", "func Halt()", SymbolVisibility::Public);
+        assert_ne!(hash(vec![serve.clone(), renamed]), baseline);
+        let retyped = entry(
+            "Serve",
+            "func Serve(addr string, port int) error",
+            SymbolVisibility::Public,
+        );
+        assert_ne!(hash(vec![retyped, stop]), baseline);
+        assert_ne!(hash(vec![serve]), baseline);
+    }
+}
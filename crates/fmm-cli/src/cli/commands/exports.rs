@@ -1,9 +1,12 @@
 use anyhow::Result;
 use clap::Args;
+use clap::builder::PossibleValuesParser;
 use colored::Colorize;
 use fmm_core::config::{Config, FileTypeFilter};
 use fmm_core::manifest::{FileEntry, Manifest};
+use fmm_core::parser::DeclarationKind;
 
+use super::api_hash::print_api_hashes;
 use super::{load_manifest, missing_file_diagnostic, warn_no_sidecars};
 
 #[derive(Args)]
@@ -24,6 +27,18 @@ pub struct ExportsCommandArgs {
     #[arg(long, default_value = "all", value_parser = ["all", "source", "tests"])]
     pub filter: String,
 
+    /// Only list exports of this declaration kind; repeat to allow several
+    #[arg(
+        long = "kind",
+        value_name = "KIND",
+        value_parser = PossibleValuesParser::new(DeclarationKind::ALL.map(DeclarationKind::as_str)),
+    )]
+    pub kinds: Vec<String>,
+
+    /// Drop exports whose bare name (without the `Type.` prefix) matches this regex
+    #[arg(long = "name-exclude", value_name = "REGEX")]
+    pub name_exclude: Option<String>,
+
     /// Maximum number of results (default: 200)
     #[arg(long)]
     pub limit: Option<usize>,
@@ -35,8 +50,20 @@ pub struct ExportsCommandArgs {
     /// Output as JSON
     #[arg(short = 'j', long = "json")]
     pub json: bool,
+
+    /// Print a SHA-256 per Go package over its exported signatures, to catch API changes in CI
+    #[arg(
+        long = "api-hash",
+        conflicts_with_all = ["pattern", "file", "filter", "kinds", "name_exclude", "limit", "offset"],
+    )]
+    pub api_hash: bool,
 }
 
+/// `fmm version` feature names for the flags above. A name is only ever
+/// added, so a new flag appends its own entry here.
+pub(crate) const EXPORTS_FEATURES: &[&str] =
+    &["exports-kind", "exports-name-exclude", "exports-api-hash"];
+
 type ExportMatch = (String, String, Option<[usize; 2]>);
 type ExportMatcher = Box<dyn Fn(&str) -> bool>;
 
@@ -44,6 +71,10 @@ struct ExportFileFilter<'a> {
     directory: Option<&'a str>,
     file_type: FileTypeFilter,
     config: &'a Config,
+    /// Declaration kinds to keep; empty keeps every kind.
+    kinds: &'a [String],
+    /// Names to drop, matched against the bare identifier.
+    name_exclude: Option<&'a regex::Regex>,
 }
 
 impl ExportFileFilter<'_> {
@@ -51,15 +82,67 @@ impl ExportFileFilter<'_> {
         path_matches_directory(path, self.directory)
     }
 
+    /// Symbols without a recorded kind never match an explicit `--kind`.
+    fn matches_kind(&self, manifest: &Manifest, name: &str, path: &str) -> bool {
+        self.kinds.is_empty()
+            || manifest
+                .declaration_kind_for(name, path)
+                .is_some_and(|kind| self.kinds.iter().any(|wanted| wanted == kind))
+    }
+
+    /// `Handler.validate` is matched as `validate`.
+    fn matches_name(&self, name: &str) -> bool {
+        let bare = name.rsplit('.').next().unwrap_or(name);
+        self.name_exclude
+            .is_none_or(|exclude| !exclude.is_match(bare))
+    }
+
     fn matches_export(&self, manifest: &Manifest, name: &str, path: &str) -> bool {
         self.matches_path(path)
             && manifest.export_matches_filter(name, path, self.file_type, self.config)
+            && self.matches_kind(manifest, name, path)
+            && self.matches_name(name)
     }
 
     fn filtered_entry(&self, manifest: &Manifest, file: &str) -> Option<FileEntry> {
-        self.matches_path(file)
+        let entry = self
+            .matches_path(file)
             .then(|| manifest.filtered_file_entry(file, self.file_type, self.config))
-            .flatten()
+            .flatten()?;
+        if self.kinds.is_empty() && self.name_exclude.is_none() {
+            return Some(entry);
+        }
+        Some(self.retain_selected(manifest, file, entry))
+    }
+
+    /// Drop exports whose kind is not selected or whose name is excluded,
+    /// keeping `export_lines` aligned with `exports`.
+    fn retain_selected(&self, manifest: &Manifest, file: &str, entry: FileEntry) -> FileEntry {
+        let mut filtered = entry.clone();
+        filtered.exports.clear();
+        filtered.export_metadata.clear();
+        let mut export_lines = entry.export_lines.as_ref().map(|_| Vec::new());
+
+        for (index, name) in entry.exports.iter().enumerate() {
+            if !self.matches_kind(manifest, name, file) || !self.matches_name(name) {
+                continue;
+            }
+            filtered.exports.push(name.clone());
+            if let Some(metadata) = entry.export_metadata.get(name) {
+                filtered
+                    .export_metadata
+                    .insert(name.clone(), metadata.clone());
+            }
+            if let (Some(source_lines), Some(filtered_lines)) =
+                (entry.export_lines.as_ref(), export_lines.as_mut())
+                && let Some(line_range) = source_lines.get(index)
+            {
+                filtered_lines.push(line_range.clone());
+            }
+        }
+
+        filtered.export_lines = export_lines;
+        filtered
     }
 }
 
@@ -71,26 +154,44 @@ struct ExportJson {
     lines: Option<[usize; 2]>,
 }
 
-pub fn exports(
-    pattern: Option<&str>,
-    file: Option<&str>,
-    directory: Option<&str>,
-    filter: &str,
-    limit: Option<usize>,
-    offset: usize,
-    json_output: bool,
-) -> Result<()> {
+/// Everything `fmm exports` takes, one field per flag.
+#[derive(Default)]
+pub struct ExportsOptions {
+    pub pattern: Option<String>,
+    pub file: Option<String>,
+    pub directory: Option<String>,
+    pub filter: String,
+    pub kinds: Vec<String>,
+    pub name_exclude: Option<String>,
+    pub limit: Option<usize>,
+    pub offset: usize,
+    pub json_output: bool,
+    pub api_hash: bool,
+}
+
+pub fn exports(options: ExportsOptions) -> Result<()> {
+    let pattern = options.pattern.as_deref();
+    let file = options.file.as_deref();
+    let directory = options.directory.as_deref();
     if file.is_some() {
         validate_file_args(pattern, directory)?;
     }
+    let name_exclude = options
+        .name_exclude
+        .as_deref()
+        .map(regex::Regex::new)
+        .transpose()
+        .map_err(|e| anyhow::anyhow!("Invalid --name-exclude: {e}"))?;
 
     let (root, manifest) = load_manifest()?;
     let config = Config::load_from_dir(&root).unwrap_or_default();
-    let file_filter = FileTypeFilter::parse(filter).unwrap_or(FileTypeFilter::All);
+    let file_filter = FileTypeFilter::parse(&options.filter).unwrap_or(FileTypeFilter::All);
     let export_filter = ExportFileFilter {
         directory,
         file_type: file_filter,
         config: &config,
+        kinds: &options.kinds,
+        name_exclude: name_exclude.as_ref(),
     };
 
     if manifest.files.is_empty() {
@@ -98,7 +199,11 @@ pub fn exports(
         return Ok(());
     }
 
-    if let Some(file_path) = file {
+    let (limit, offset) = (options.limit, options.offset);
+    let json_output = options.json_output;
+    if options.api_hash {
+        print_api_hashes(&root, &manifest, &config, directory, json_output)?;
+    } else if let Some(file_path) = file {
         print_file_exports(&root, &manifest, file_path, &export_filter, json_output)?;
     } else if let Some(pat) = pattern {
         print_pattern_exports(&manifest, pat, &export_filter, limit, offset, json_output)?;
@@ -277,7 +382,7 @@ fn print_all_exports(
     Ok(())
 }
 
-fn path_matches_directory(path: &str, directory: Option<&str>) -> bool {
+pub(super) fn path_matches_directory(path: &str, directory: Option<&str>) -> bool {
     directory.is_none_or(|dir| path.starts_with(dir))
 }
 
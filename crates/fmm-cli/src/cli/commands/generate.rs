@@ -22,7 +22,46 @@ pub struct GenerateCommandArgs {
     #[arg(long)]
     pub no_git: bool,
 
+    /// Index only the files directly inside each directory, not subdirectories
+    #[arg(long)]
+    pub no_recurse: bool,
+
+    /// Index only files matching this glob, relative to the scan root (repeatable)
+    #[arg(long, value_name = "GLOB")]
+    pub include: Vec<String>,
+
+    /// Skip files matching this glob, relative to the scan root; wins over --include (repeatable)
+    #[arg(long, value_name = "GLOB")]
+    pub exclude: Vec<String>,
+
+    /// Abort without writing the index if any file fails to parse
+    #[arg(long)]
+    pub fail_on_parse_error: bool,
+
+    /// Print file, line and symbol counts for the indexed files to stderr
+    #[arg(long)]
+    pub stats: bool,
+
+    /// Print `{"indexed": N, "errors": [{"file", "error"}]}` to stdout instead of the summary lines
+    #[arg(long, conflicts_with = "dry_run")]
+    pub json: bool,
+
     /// Suppress progress bars — print only the final summary line
     #[arg(short = 'q', long)]
     pub quiet: bool,
+
+    /// Number of parser worker threads (defaults to the number of CPUs)
+    #[arg(long, value_name = "N")]
+    pub jobs: Option<std::num::NonZeroUsize>,
 }
+
+/// `fmm version` feature names for the flags above. A name is only ever
+/// added, so a new flag appends its own entry here.
+pub(crate) const GENERATE_FEATURES: &[&str] = &[
+    "generate-include-exclude",
+    "generate-stats",
+    "fail-on-parse-error",
+    "generate-jobs",
+    "generate-no-recurse",
+    "generate-json",
+];
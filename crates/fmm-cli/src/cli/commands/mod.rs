@@ -5,6 +5,7 @@ use fmm_core::manifest::Manifest;
 use fmm_core::store::FmmStore;
 use fmm_store::SqliteStore;
 
+mod api_hash;
 mod clean;
 mod completions;
 mod cycles;
@@ -17,10 +18,12 @@ mod init;
 mod lookup;
 mod ls;
 mod outline;
+mod outline_reports;
 mod read;
 mod search;
 mod similar;
 mod validate;
+mod version;
 mod watch;
 
 pub use clean::CleanCommandArgs;
@@ -31,8 +34,10 @@ pub use deps::DepsCommandArgs;
 pub use deps::deps;
 pub use dupes::DupesCommandArgs;
 pub use dupes::dupes;
+pub(crate) use exports::EXPORTS_FEATURES;
 pub use exports::ExportsCommandArgs;
-pub use exports::exports;
+pub use exports::{ExportsOptions, exports};
+pub(crate) use generate::GENERATE_FEATURES;
 pub use generate::GenerateCommandArgs;
 pub use glossary::GlossaryCommandArgs;
 pub use init::InitCommandArgs;
@@ -40,14 +45,16 @@ pub use lookup::LookupCommandArgs;
 pub use lookup::lookup;
 pub use ls::LsCommandArgs;
 pub use ls::ls;
+pub(crate) use outline::OUTLINE_FEATURES;
 pub use outline::OutlineCommandArgs;
-pub use outline::outline;
+pub use outline::{OutlineOptions, outline};
 pub use read::ReadCommandArgs;
 pub use read::read_symbol;
 pub use search::SearchCommandArgs;
 pub use similar::SimilarCommandArgs;
 pub use similar::similar;
 pub use validate::ValidateCommandArgs;
+pub use version::VersionCommandArgs;
 pub use watch::WatchCommandArgs;
 
 fn load_manifest() -> Result<(std::path::PathBuf, Manifest)> {
@@ -1,32 +1,266 @@
-use anyhow::Result;
+use anyhow::{Context, Result};
 use clap::Args;
 use colored::Colorize;
-use fmm_core::manifest::OutlineReExport;
+use fmm_core::config::Config;
+use fmm_core::extractor::FileProcessor;
+use fmm_core::format::{OutlineLayout, OutlineNotes, OutlineSort, sort_outline_symbols};
+use fmm_core::manifest::{FileEntry, Manifest, OutlineReExport, SymbolMetadata};
+use fmm_core::parser::builtin::go::{markers_with_tags, qualify_selectors, split_expression_list};
+use serde_json::{Map, Value};
+use std::collections::HashMap;
+use std::io::{Read, Write};
+use std::path::Path;
+use std::process::{Command, Stdio};
 
 use crate::outline_freshness;
 
+use super::outline_reports::OutlineReport;
 use super::{load_manifest, missing_file_diagnostic, warn_no_sidecars};
 
 #[derive(Args)]
 pub struct OutlineCommandArgs {
-    /// Source file path (relative to project root)
+    /// Source file path (relative to project root), or `-` to read from stdin
     #[arg(value_name = "FILE")]
     pub file: String,
 
     /// Include private/protected methods and fields under each class
-    #[arg(long = "include-private")]
+    #[arg(long = "include-private", conflicts_with = "report")]
     pub include_private: bool,
 
+    /// Language extension of source read from stdin (e.g. `go`)
+    #[arg(long = "lang", value_name = "EXT")]
+    pub lang: Option<String>,
+
     /// Output as JSON
     #[arg(short = 'j', long = "json")]
     pub json: bool,
+
+    /// One signature per line, without positions or metadata
+    #[arg(long = "signature-only", conflicts_with_all = ["json", "include_private", "report"])]
+    pub signature_only: bool,
+
+    /// Indented tree of symbols and their members, colored by kind on a terminal
+    #[arg(long, conflicts_with_all = ["json", "signature_only", "include_private", "report"])]
+    pub tree: bool,
+
+    /// Write package selectors in signatures as full import paths (`*github.com/redis/go-redis/v9.Client`)
+    #[arg(long = "qualify-imports", conflicts_with = "report")]
+    pub qualify_imports: bool,
+
+    /// List the methods a Go interface gets from interfaces it embeds from the same file
+    #[arg(long = "expand-embeds", conflicts_with = "report")]
+    pub expand_embeds: bool,
+
+    /// Never color output (NO_COLOR in the environment does the same)
+    #[arg(long)]
+    pub no_color: bool,
+
+    /// Symbol order: source (default), name, kind (then name), or godoc (go doc grouping); members sort within their parent
+    #[arg(long, value_name = "ORDER", default_value = "source", value_parser = ["source", "name", "kind", "godoc"])]
+    pub sort: String,
+
+    /// Add the first N body lines of each function and method as `snippet` (with --json)
+    #[arg(long, value_name = "N", requires = "json", conflicts_with = "report")]
+    pub context: Option<usize>,
+
+    /// Pipe symbol names as a JSON array to CMD and add the objects it prints back as `annotations` (with --json)
+    #[arg(
+        long = "annotate-cmd",
+        value_name = "CMD",
+        requires = "json",
+        conflicts_with = "report"
+    )]
+    pub annotate_cmd: Option<String>,
+
+    /// Leave out functions and methods with cyclomatic complexity below N (with --json)
+    #[arg(
+        long = "min-complexity",
+        value_name = "N",
+        requires = "json",
+        conflicts_with = "report"
+    )]
+    pub min_complexity: Option<u64>,
+
+    /// Add TODO/FIXME/HACK comments to the function holding them as `markers`, the rest at top level (with --json)
+    #[arg(long, requires = "json", conflicts_with = "report")]
+    pub markers: bool,
+
+    /// Add each constant's `value`, with integer and string expressions folded (`1 << 10` is `1024`) (with --json)
+    #[arg(long = "eval-consts", requires = "json", conflicts_with = "report")]
+    pub eval_consts: bool,
+
+    /// List only deprecated symbols, and types with deprecated methods (with --json)
+    #[arg(long = "only-deprecated", requires = "json", conflicts_with = "report")]
+    pub only_deprecated: bool,
+
+    /// Also treat TAG (e.g. `XXX`) as a marker in Go files; repeat to add several (with --markers)
+    #[arg(long = "marker-tag", value_name = "TAG", requires = "markers")]
+    pub marker_tags: Vec<String>,
+
+    /// Print the calls between this file's functions and methods as a Graphviz DOT graph, or as JSON with --json
+    #[arg(long, group = "report")]
+    pub callgraph: bool,
+
+    /// List exported symbols without a doc comment, failing when there are more than --max-undocumented
+    #[arg(long, group = "report")]
+    pub undocumented: bool,
+
+    /// Undocumented exported symbols --undocumented allows before failing
+    #[arg(
+        long = "max-undocumented",
+        value_name = "N",
+        default_value_t = 0,
+        requires = "undocumented"
+    )]
+    pub max_undocumented: usize,
+
+    /// List receiver types whose methods mix pointer and value receivers or receiver names
+    #[arg(long, group = "report")]
+    pub receiver_report: bool,
+
+    /// List this file's imports by kind (stdlib, third party, in-module), marking unused ones, without its symbols
+    #[arg(long, group = "report")]
+    pub imports_only: bool,
+
+    /// Print each struct's estimated size, field offsets and padding, with a smaller field order when one exists
+    #[arg(long, group = "report")]
+    pub layout: bool,
+
+    /// List functions and methods returning the built-in error, and whether it is the last result
+    #[arg(long, group = "report")]
+    pub error_report: bool,
+
+    /// List locals, parameters and receivers in functions that shadow a package-level name declared in this file
+    #[arg(long, group = "report")]
+    pub shadow_report: bool,
 }
 
+/// `fmm version` feature names for the flags above. A name is only ever
+/// added, so a new flag appends its own entry here.
+pub(crate) const OUTLINE_FEATURES: &[&str] = &[
+    "outline-stdin",
+    "outline-sort",
+    "outline-context",
+    "outline-sort-godoc",
+    "outline-annotate-cmd",
+    "outline-min-complexity",
+    "outline-markers",
+    "outline-callgraph",
+    "outline-undocumented",
+    "outline-eval-consts",
+    "outline-receiver-report",
+    "outline-only-deprecated",
+    "outline-imports-only",
+    "outline-layout",
+    "outline-error-report",
+    "outline-shadow-report",
+    "outline-qualify-imports",
+    "outline-expand-embeds",
+];
+
 #[derive(serde::Serialize)]
 struct OutlineExportJson {
     name: String,
     #[serde(skip_serializing_if = "Option::is_none")]
     lines: Option<[usize; 2]>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    size: Option<usize>,
+    #[serde(flatten)]
+    metadata: OutlineMetadataJson,
+    #[serde(flatten)]
+    analysis: OutlineAnalysisJson,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    snippet: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    annotations: Option<Map<String, Value>>,
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    members: Vec<OutlineMemberJson>,
+}
+
+#[derive(serde::Serialize)]
+struct OutlineMemberJson {
+    name: String,
+    lines: [usize; 2],
+    size: usize,
+    #[serde(flatten)]
+    metadata: OutlineMetadataJson,
+    #[serde(flatten)]
+    analysis: OutlineAnalysisJson,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    snippet: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    annotations: Option<Map<String, Value>>,
+}
+
+/// Same metadata the YAML outline renders as `signature:`, `visibility:` and
+/// `kind:` lines. Absent values are omitted rather than emitted as null.
+#[derive(serde::Serialize, Default)]
+struct OutlineMetadataJson {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    signature: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    visibility: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    kind: Option<String>,
+}
+
+impl From<&SymbolMetadata> for OutlineMetadataJson {
+    fn from(metadata: &SymbolMetadata) -> Self {
+        Self {
+            signature: metadata.signature.clone(),
+            visibility: metadata.visibility.clone(),
+            kind: metadata.declaration_kind.clone(),
+        }
+    }
+}
+
+/// Per-symbol facts from a fresh parse of the file, which the index does not
+/// keep. Only the Go parser computes them so far; absent values are omitted.
+#[derive(serde::Serialize, Default)]
+struct OutlineAnalysisJson {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    complexity: Option<u64>,
+    /// Lines inside a function or method body, unlike `size`, which counts
+    /// the signature and braces too. Bodyless declarations report 0.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    loc: Option<u64>,
+    /// Set on struct fields that embed a type rather than name a field.
+    #[serde(skip_serializing_if = "std::ops::Not::not")]
+    embedded: bool,
+    /// `test_main` for `TestMain`; `init` and `main` are never exported.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    special: Option<String>,
+    /// Set on every symbol of a test file.
+    #[serde(skip_serializing_if = "std::ops::Not::not")]
+    test: bool,
+    /// `test`, `benchmark`, `fuzz` or `example` for a function `go test` runs.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    test_kind: Option<String>,
+    /// Receiver type of a method listed at the top level because the type is
+    /// declared in another file.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    external_receiver: Option<String>,
+    /// Interfaces an interface embeds, as written.
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    embeds: Vec<String>,
+    /// With `--expand-embeds`, the embedded interface a method comes from.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    promoted_from: Option<String>,
+    /// Lines of the `const (...)` block the constant is declared in.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    block: Option<[u64; 2]>,
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    markers: Vec<Value>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    value: Option<String>,
+    #[serde(skip_serializing_if = "std::ops::Not::not")]
+    deprecated: bool,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    deprecation_note: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    error_return: Option<bool>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    error_return_position: Option<u64>,
 }
 
 #[derive(serde::Serialize)]
@@ -40,6 +274,16 @@ struct OutlineReExportJson {
 #[derive(serde::Serialize)]
 struct OutlineJson {
     file: String,
+    /// The Go `package` clause; an external test package keeps its `_test` suffix.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    package: Option<String>,
+    /// From go.mod, or the directory under the project root without one.
+    #[serde(skip_serializing_if = "Option::is_none")]
+    import_path: Option<String>,
+    #[serde(skip_serializing_if = "std::ops::Not::not")]
+    test: bool,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    package_doc: Option<String>,
     #[serde(skip_serializing_if = "Vec::is_empty")]
     imports: Vec<String>,
     #[serde(skip_serializing_if = "Vec::is_empty")]
@@ -47,10 +291,271 @@ struct OutlineJson {
     exports: Vec<OutlineExportJson>,
     #[serde(skip_serializing_if = "Vec::is_empty")]
     reexports: Vec<OutlineReExportJson>,
+    /// `--markers` found outside the listed functions: at package scope, or
+    /// in a function the outline leaves out, which `function` then names.
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    markers: Vec<Value>,
     loc: usize,
 }
 
-pub fn outline(file: &str, include_private: bool, json_output: bool) -> Result<()> {
+/// Synthetic file name reported for source piped through `fmm outline -`.
+const STDIN_FILE: &str = "<stdin>";
+
+/// The text of the file being outlined and the language-specific fields of
+/// a fresh parse of it, which the index does not keep.
+pub(super) struct OutlineSource {
+    pub(super) text: String,
+    pub(super) fields: HashMap<String, Value>,
+    pub(super) is_go: bool,
+    /// Whether the file is a test file by the configured test patterns, as
+    /// `fmm exports --filter tests` decides.
+    pub(super) is_test: bool,
+    /// Methods `--expand-embeds` added, keyed `Interface.Method`, with the
+    /// embedded interface that declares each.
+    pub(super) promoted: HashMap<String, String>,
+}
+
+impl OutlineSource {
+    fn read(root: &Path, file: &str) -> Result<Self> {
+        let path = root.join(file);
+        let is_go = path.extension().is_some_and(|ext| ext == "go");
+        let text =
+            std::fs::read_to_string(&path).with_context(|| format!("Failed to read {file}"))?;
+        let mut fields = FileProcessor::new(root)
+            .parse_content(&path, &text)
+            .with_context(|| format!("Failed to parse {file}"))?
+            .custom_fields
+            .unwrap_or_default();
+        // The parser only knows import paths under a go.mod; outside one the
+        // package is named by its directory under the project root.
+        if is_go && !fields.contains_key("import_path") {
+            fields.insert(
+                "import_path".to_string(),
+                Value::String(directory_import_path(file)),
+            );
+        }
+        Ok(Self {
+            text,
+            fields,
+            is_go,
+            is_test: Config::load_from_dir(root)
+                .unwrap_or_default()
+                .is_test_file(file),
+            promoted: HashMap::new(),
+        })
+    }
+
+    /// `fields[key]` when it is a string.
+    fn file_text(&self, key: &str) -> Option<String> {
+        self.fields.get(key)?.as_str().map(str::to_string)
+    }
+
+    /// Line range of the parenthesized `const (...)` block declaring each
+    /// constant, from `const_blocks`. Constants declared on their own are absent.
+    fn const_blocks(&self) -> HashMap<String, [u64; 2]> {
+        let Some(Value::Array(blocks)) = self.fields.get("const_blocks") else {
+            return HashMap::new();
+        };
+        let mut by_name = HashMap::new();
+        for block in blocks {
+            let (Some(start), Some(end)) = (block["lines"][0].as_u64(), block["lines"][1].as_u64())
+            else {
+                continue;
+            };
+            for name in block["names"].as_array().into_iter().flatten() {
+                if let Some(name) = name.as_str() {
+                    by_name.insert(name.to_string(), [start, end]);
+                }
+            }
+        }
+        by_name
+    }
+
+    /// Embedded struct fields as the `Struct.Field` members they are indexed
+    /// as, from `embedded_fields`: `*Base` embedded in `Server` is `Server.Base`.
+    fn embedded_fields(&self) -> std::collections::HashSet<String> {
+        let Some(Value::Object(by_struct)) = self.fields.get("embedded_fields") else {
+            return std::collections::HashSet::new();
+        };
+        by_struct
+            .iter()
+            .flat_map(|(parent, embeds)| {
+                embeds
+                    .as_array()
+                    .into_iter()
+                    .flatten()
+                    .filter_map(Value::as_str)
+                    .map(move |embed| format!("{parent}.{}", embedded_field_name(embed)))
+            })
+            .collect()
+    }
+
+    /// Interfaces embedded in each interface, as written, from
+    /// `embedded_interfaces`.
+    fn embedded_interfaces(&self) -> HashMap<String, Vec<String>> {
+        let Some(Value::Object(by_interface)) = self.fields.get("embedded_interfaces") else {
+            return HashMap::new();
+        };
+        by_interface
+            .iter()
+            .map(|(interface, embeds)| {
+                let embeds = embeds.as_array().into_iter().flatten();
+                let embeds = embeds.filter_map(Value::as_str).map(str::to_string);
+                (interface.clone(), embeds.collect())
+            })
+            .collect()
+    }
+
+    /// `entry` with the methods each interface gets from the interfaces it
+    /// embeds, for `--expand-embeds`, recording where each came from in
+    /// `promoted`. Only interfaces indexed in this file can be expanded;
+    /// those from other packages stay listed as `embeds`.
+    fn expand_embeds(&mut self, entry: &FileEntry) -> FileEntry {
+        let embeds = self.embedded_interfaces();
+        let declared = entry.methods.clone().unwrap_or_default();
+        let mut expanded = entry.clone();
+        let methods = expanded.methods.get_or_insert_with(HashMap::new);
+        for (interface, direct) in &embeds {
+            let mut seen = std::collections::HashSet::from([interface.as_str()]);
+            let mut pending: Vec<&str> = direct.iter().filter_map(|e| local_interface(e)).collect();
+            while let Some(embedded) = pending.pop() {
+                if !seen.insert(embedded) {
+                    continue;
+                }
+                let prefix = format!("{embedded}.");
+                for (origin, lines) in &declared {
+                    let Some(method) = origin.strip_prefix(&prefix) else {
+                        continue;
+                    };
+                    let promoted = format!("{interface}.{method}");
+                    if methods.contains_key(&promoted) {
+                        continue;
+                    }
+                    methods.insert(promoted.clone(), lines.clone());
+                    if let Some(metadata) = entry.method_metadata.get(origin) {
+                        expanded
+                            .method_metadata
+                            .insert(promoted.clone(), metadata.clone());
+                    }
+                    self.promoted.insert(promoted, embedded.to_string());
+                }
+                let nested = embeds.get(embedded).into_iter().flatten();
+                pending.extend(nested.filter_map(|e| local_interface(e)));
+            }
+        }
+        expanded
+    }
+
+    /// Lines inside each function and method body, from `body_lines`, keyed
+    /// by the symbol `entry` lists it as: a method on a type declared in
+    /// another file is listed by its name alone. Interface methods have no
+    /// body and report 0.
+    fn body_lines(&self, entry: &FileEntry) -> HashMap<String, u64> {
+        let mut by_symbol = HashMap::new();
+        if !self.is_go {
+            return by_symbol;
+        }
+        for (member, metadata) in &entry.method_metadata {
+            if metadata.declaration_kind.as_deref() == Some("method") {
+                by_symbol.insert(member.clone(), 0);
+            }
+        }
+        let Some(Value::Object(lines)) = self.fields.get("body_lines") else {
+            return by_symbol;
+        };
+        let external = self.external_receivers();
+        for (key, count) in lines {
+            // The `init#N` functions of a file all appear as `init`, so none
+            // of their counts can be told apart in the outline.
+            let Some(count) = count.as_u64().filter(|_| !key.contains('#')) else {
+                continue;
+            };
+            let symbol = match key.split_once('.') {
+                Some((receiver, method)) if external.get(method) == Some(&receiver) => method,
+                _ => key,
+            };
+            by_symbol.insert(symbol.to_string(), count);
+        }
+        by_symbol
+    }
+
+    /// Method -> receiver type for the exported methods on a type declared
+    /// outside this file, from `external_receivers`. The index lists them by
+    /// method name at the top level, keeping the first of several.
+    fn external_receivers(&self) -> HashMap<&str, &str> {
+        let mut by_method = HashMap::new();
+        let Some(Value::Array(methods)) = self.fields.get("external_receivers") else {
+            return by_method;
+        };
+        let dotted = methods.iter().filter_map(Value::as_str);
+        for (receiver, method) in dotted.filter_map(|dotted| dotted.split_once('.')) {
+            by_method.entry(method).or_insert(receiver);
+        }
+        by_method
+    }
+
+    /// `fields[key][symbol]`, where `symbol` is `name` or `Parent.name`.
+    fn symbol_field(&self, key: &str, symbol: &str) -> Option<&Value> {
+        self.fields.get(key)?.get(symbol)
+    }
+}
+
+/// Everything `fmm outline` takes besides the file, one field per flag.
+#[derive(Default)]
+pub struct OutlineOptions {
+    pub include_private: bool,
+    pub lang: Option<String>,
+    pub json_output: bool,
+    pub signature_only: bool,
+    pub tree: bool,
+    pub qualify_imports: bool,
+    pub expand_embeds: bool,
+    pub sort: String,
+    pub context: Option<usize>,
+    pub annotate_cmd: Option<String>,
+    pub min_complexity: Option<u64>,
+    pub markers: bool,
+    pub marker_tags: Vec<String>,
+    pub eval_consts: bool,
+    pub only_deprecated: bool,
+    pub callgraph: bool,
+    pub undocumented: bool,
+    pub max_undocumented: usize,
+    pub receiver_report: bool,
+    pub imports_only: bool,
+    pub layout: bool,
+    pub error_report: bool,
+    pub shadow_report: bool,
+}
+
+impl OutlineOptions {
+    /// The report a report flag selects, printed in place of the outline.
+    fn report(&self) -> Option<OutlineReport> {
+        [
+            (self.callgraph, OutlineReport::Callgraph),
+            (
+                self.undocumented,
+                OutlineReport::Undocumented {
+                    max: self.max_undocumented,
+                },
+            ),
+            (self.receiver_report, OutlineReport::Receivers),
+            (self.imports_only, OutlineReport::Imports),
+            (self.layout, OutlineReport::Layout),
+            (self.error_report, OutlineReport::Errors),
+            (self.shadow_report, OutlineReport::Shadows),
+        ]
+        .into_iter()
+        .find_map(|(selected, report)| selected.then_some(report))
+    }
+}
+
+pub fn outline(file: &str, options: OutlineOptions) -> Result<()> {
+    if file == "-" {
+        return outline_stdin(&options);
+    }
+    let sort = outline_sort(&options.sort);
+
     let (root, manifest) = load_manifest()?;
 
     if manifest.files.is_empty() {
@@ -71,49 +576,42 @@ pub fn outline(file: &str, include_private: bool, json_output: bool) -> Result<(
         .get(file)
         .ok_or_else(|| anyhow::anyhow!(missing_file_diagnostic(&root, file)))?;
 
+    // One parse serves the reports, JSON and the flags reshaping the entry,
+    // which all need it; the text outline only loses its notes without it.
+    let source = OutlineSource::read(&root, file);
+    if let Some(report) = options.report() {
+        return report.print(file, &source?, options.json_output);
+    }
+    let mut source = if options.json_output || options.qualify_imports || options.expand_embeds {
+        Some(source?)
+    } else {
+        source.ok()
+    };
+
+    let aliases = source
+        .as_ref()
+        .filter(|_| options.qualify_imports)
+        .map(import_aliases);
+    let mut reshaped = aliases
+        .as_ref()
+        .map(|aliases| qualify_entry(entry, aliases));
+    if let Some(source) = source.as_mut().filter(|_| options.expand_embeds) {
+        reshaped = Some(source.expand_embeds(reshaped.as_ref().unwrap_or(entry)));
+    }
+    let entry = reshaped.as_ref().unwrap_or(entry);
+
     let reexports = manifest.reexports_in_file(file);
     let reexport_names: std::collections::HashSet<&str> =
         reexports.iter().map(|r| r.name.as_str()).collect();
 
-    if json_output {
-        let exports: Vec<OutlineExportJson> = entry
-            .exports
-            .iter()
-            .enumerate()
-            .filter(|(_, name)| !reexport_names.contains(name.as_str()))
-            .map(|(i, name)| {
-                let lines = entry
-                    .export_lines
-                    .as_ref()
-                    .and_then(|el| el.get(i))
-                    .filter(|l| l.start > 0)
-                    .map(|l| [l.start, l.end]);
-                OutlineExportJson {
-                    name: name.clone(),
-                    lines,
-                }
-            })
-            .collect();
-        let reexport_json: Vec<OutlineReExportJson> = reexports
-            .iter()
-            .map(|r: &OutlineReExport| OutlineReExportJson {
-                name: r.name.clone(),
-                origin_file: r.origin_file.clone(),
-                origin_start: r.origin_start,
-                origin_end: r.origin_end,
-            })
-            .collect();
-        let json = OutlineJson {
-            file: file.to_string(),
-            imports: entry.imports.clone(),
-            dependencies: entry.dependencies.clone(),
-            exports,
-            reexports: reexport_json,
-            loc: entry.loc,
-        };
-        println!("{}", serde_json::to_string_pretty(&json)?);
+    if let Some(source) = source.as_ref().filter(|_| options.json_output) {
+        print_outline_json(file, entry, &reexports, &reexport_names, source, &options)?;
+    } else if options.signature_only {
+        print_outline_signatures(entry, &reexport_names, sort);
+    } else if options.tree {
+        print_outline_tree(file, entry, &reexport_names, sort);
     } else {
-        let private_by_class = if include_private {
+        let mut private_by_class = if options.include_private {
             let class_names: Vec<&str> = entry.exports.iter().map(|s| s.as_str()).collect();
             Some(
                 fmm_core::manifest::private_members::extract_private_members(
@@ -125,7 +623,7 @@ pub fn outline(file: &str, include_private: bool, json_output: bool) -> Result<(
         } else {
             None
         };
-        let top_level_fns = if include_private {
+        let mut top_level_fns = if options.include_private {
             let export_names: Vec<&str> = entry.exports.iter().map(|s| s.as_str()).collect();
             Some(
                 fmm_core::manifest::private_members::extract_top_level_functions(
@@ -137,16 +635,36 @@ pub fn outline(file: &str, include_private: bool, json_output: bool) -> Result<(
         } else {
             None
         };
+        if let Some(aliases) = &aliases {
+            let private = private_by_class.iter_mut().flat_map(|by_class| {
+                by_class
+                    .values_mut()
+                    .flatten()
+                    .map(|member| &mut member.metadata)
+            });
+            let top_level = top_level_fns
+                .iter_mut()
+                .flatten()
+                .map(|function| &mut function.metadata);
+            for metadata in private.chain(top_level) {
+                qualify_metadata(metadata, aliases);
+            }
+        }
         let freshness = outline_freshness::outline_freshness(&root, file);
+        let notes = source
+            .as_ref()
+            .map(|source| outline_notes(source, entry))
+            .unwrap_or_default();
         println!(
             "{}",
-            fmm_core::format::format_file_outline(
+            fmm_core::format::format_file_outline_sorted(
                 file,
                 entry,
                 &reexports,
                 private_by_class.as_ref(),
                 top_level_fns.as_deref(),
                 freshness.as_deref(),
+                &OutlineLayout { sort, notes },
             )
         );
     }
@@ -154,10 +672,836 @@ pub fn outline(file: &str, include_private: bool, json_output: bool) -> Result<(
     Ok(())
 }
 
+/// Read source from stdin and print its outline without touching the index.
+///
+/// The parser is chosen from `--lang`; line ranges are relative to the piped
+/// content. Source that fails to parse is rejected rather than printed as a
+/// partial outline.
+fn outline_stdin(options: &OutlineOptions) -> Result<()> {
+    let Some(lang) = options.lang.as_deref() else {
+        anyhow::bail!(
+            "Reading from stdin needs a language. Use {}.",
+            "fmm outline - --lang <EXT>".bold()
+        );
+    };
+    if options.include_private {
+        anyhow::bail!("--include-private is not supported when reading from stdin");
+    }
+    let sort = outline_sort(&options.sort);
+
+    let mut source = String::new();
+    std::io::stdin()
+        .read_to_string(&mut source)
+        .context("Failed to read source from stdin")?;
+
+    // A path under the working directory keeps go.mod-aware import
+    // classification working for piped Go source.
+    let extension = lang.trim_start_matches('.');
+    let root = std::env::current_dir()?;
+    let path = root.join(format!("{STDIN_FILE}.{extension}"));
+    let processor = FileProcessor::new(&root);
+    if let Some(error) = processor
+        .syntax_error(&path, &source)
+        .with_context(|| format!("Failed to parse {STDIN_FILE} as .{extension}"))?
+    {
+        anyhow::bail!("{STDIN_FILE}:{error}");
+    }
+
+    let result = processor
+        .parse_content(&path, &source)
+        .with_context(|| format!("Failed to parse {STDIN_FILE} as .{extension}"))?;
+    let mut source = OutlineSource {
+        text: source,
+        fields: result.custom_fields.unwrap_or_default(),
+        is_go: extension == "go",
+        is_test: false,
+        promoted: HashMap::new(),
+    };
+    if let Some(report) = options.report() {
+        return report.print(STDIN_FILE, &source, options.json_output);
+    }
+
+    let mut manifest = Manifest::new();
+    manifest.add_file(STDIN_FILE, result.metadata);
+    let entry = manifest
+        .files
+        .get(STDIN_FILE)
+        .expect("file was just added to the manifest");
+    let mut reshaped = options
+        .qualify_imports
+        .then(|| qualify_entry(entry, &import_aliases(&source)));
+    if options.expand_embeds {
+        reshaped = Some(source.expand_embeds(reshaped.as_ref().unwrap_or(entry)));
+    }
+    let entry = reshaped.as_ref().unwrap_or(entry);
+
+    let no_reexports = std::collections::HashSet::new();
+    if options.json_output {
+        print_outline_json(STDIN_FILE, entry, &[], &no_reexports, &source, options)?;
+    } else if options.signature_only {
+        print_outline_signatures(entry, &no_reexports, sort);
+    } else if options.tree {
+        print_outline_tree(STDIN_FILE, entry, &no_reexports, sort);
+    } else {
+        println!(
+            "{}",
+            fmm_core::format::format_file_outline_sorted(
+                STDIN_FILE,
+                entry,
+                &[],
+                None,
+                None,
+                None,
+                &OutlineLayout {
+                    sort,
+                    notes: outline_notes(&source, entry),
+                },
+            )
+        );
+    }
+
+    Ok(())
+}
+
+/// `source` supplies what the index does not hold: body snippets for
+/// `--context` and the per-symbol analysis fields.
+fn print_outline_json(
+    file: &str,
+    entry: &FileEntry,
+    reexports: &[OutlineReExport],
+    reexport_names: &std::collections::HashSet<&str>,
+    source: &OutlineSource,
+    options: &OutlineOptions,
+) -> Result<()> {
+    let mut exports = outline_exports(entry, reexport_names, outline_sort(&options.sort));
+    attach_analysis(&mut exports, entry, source, options);
+    if let Some(min) = options.min_complexity {
+        retain_min_complexity(&mut exports, min);
+    }
+    if options.only_deprecated {
+        retain_deprecated(&mut exports);
+    }
+    let markers = if options.markers {
+        attach_markers(&mut exports, source, &options.marker_tags)
+    } else {
+        Vec::new()
+    };
+    if let Some(context) = options.context {
+        attach_snippets(&mut exports, &source.text, context);
+    }
+    if let Some(command) = &options.annotate_cmd {
+        attach_annotations(&mut exports, command);
+    }
+    let reexport_json: Vec<OutlineReExportJson> = reexports
+        .iter()
+        .map(|r| OutlineReExportJson {
+            name: r.name.clone(),
+            origin_file: r.origin_file.clone(),
+            origin_start: r.origin_start,
+            origin_end: r.origin_end,
+        })
+        .collect();
+    let json = OutlineJson {
+        file: file.to_string(),
+        package: source.file_text("package"),
+        import_path: source.file_text("import_path"),
+        test: source.is_test,
+        package_doc: source.file_text("package_doc"),
+        imports: entry.imports.clone(),
+        dependencies: entry.dependencies.clone(),
+        exports,
+        reexports: reexport_json,
+        markers,
+        loc: entry.loc,
+    };
+    println!("{}", serde_json::to_string_pretty(&json)?);
+    Ok(())
+}
+
+/// The field name an embedded type gives its struct: the type name without
+/// pointer, package or type arguments, so `*pkg.List[T]` is `List`.
+fn embedded_field_name(embed: &str) -> &str {
+    let embed = embed.trim_start_matches('*');
+    let embed = embed.split('[').next().unwrap_or(embed);
+    embed.rsplit('.').next().unwrap_or(embed).trim()
+}
+
+/// `file`'s directory relative to the project root, written as Go writes
+/// import paths: `internal/server`, or `.` at the root.
+fn directory_import_path(file: &str) -> String {
+    let segments: Vec<_> = Path::new(file)
+        .parent()
+        .into_iter()
+        .flat_map(Path::components)
+        .map(|component| component.as_os_str().to_string_lossy())
+        .collect();
+    if segments.is_empty() {
+        ".".to_string()
+    } else {
+        segments.join("/")
+    }
+}
+
+/// The interface an embed names when it is declared in the same package:
+/// `Closer` or `List[T]` is, `io.Reader` is not.
+fn local_interface(embed: &str) -> Option<&str> {
+    let name = embed.split('[').next().unwrap_or(embed).trim();
+    (!name.contains('.')).then_some(name)
+}
+
+/// Package selector -> import path for the imports of `source`.
+fn import_aliases(source: &OutlineSource) -> Map<String, Value> {
+    match source.fields.get("import_aliases") {
+        Some(Value::Object(aliases)) => aliases.clone(),
+        _ => Map::new(),
+    }
+}
+
+/// `entry` with the package selectors in its signatures written as import
+/// paths, for `--qualify-imports`. Dot-imported names carry no selector and
+/// are left as written.
+fn qualify_entry(entry: &FileEntry, aliases: &Map<String, Value>) -> FileEntry {
+    let mut entry = entry.clone();
+    for metadata in entry
+        .export_metadata
+        .values_mut()
+        .chain(entry.method_metadata.values_mut())
+    {
+        qualify_metadata(metadata, aliases);
+    }
+    entry
+}
+
+fn qualify_metadata(metadata: &mut SymbolMetadata, aliases: &Map<String, Value>) {
+    if let Some(signature) = &mut metadata.signature {
+        *signature = qualify_selectors(signature, aliases);
+    }
+}
+
+/// Rows the YAML outline adds from the parse of `source` to `entry`, its
+/// outline from the index.
+fn outline_notes(source: &OutlineSource, entry: &FileEntry) -> OutlineNotes {
+    let mut notes = OutlineNotes::default();
+    for (symbol, count) in source.body_lines(entry) {
+        notes.add_symbol(&symbol, "loc", count.to_string());
+    }
+    if let Some(package) = source.file_text("package") {
+        notes.add_file("package", package);
+    }
+    if let Some(import_path) = source.file_text("import_path") {
+        notes.add_file("import_path", import_path);
+    }
+    if source.is_test {
+        notes.add_file("test", "true");
+    }
+    if let Some(doc) = source.file_text("package_doc") {
+        notes.add_file("package_doc", doc);
+    }
+    if let Some(Value::Object(special)) = source.fields.get("special_functions") {
+        // Every `init#N` is listed as `init`, so one note covers all of them.
+        let mut noted = std::collections::HashSet::new();
+        for (key, role) in special {
+            let name = key.split('#').next().unwrap_or(key);
+            if let (true, Some(role)) = (noted.insert(name), role.as_str()) {
+                notes.add_symbol(name, "special", role);
+            }
+        }
+    }
+    for (method, receiver) in source.external_receivers() {
+        // A function of the same name wins the top-level slot in the index.
+        let kind = entry
+            .export_metadata
+            .get(method)
+            .and_then(|m| m.declaration_kind.as_deref());
+        if kind == Some("method") {
+            notes.add_symbol(method, "external_receiver", receiver);
+        }
+    }
+    if let Some(Value::Object(test_kinds)) = source.fields.get("test_kind") {
+        for (name, kind) in test_kinds {
+            if let Some(kind) = kind.as_str() {
+                notes.add_symbol(name, "test_kind", kind);
+            }
+        }
+    }
+    for (name, lines) in source.const_blocks() {
+        notes.add_symbol(&name, "block", lines.map(|line| line.to_string()).to_vec());
+    }
+    for member in source.embedded_fields() {
+        notes.add_symbol(&member, "embedded", "true");
+    }
+    for (interface, embeds) in source.embedded_interfaces() {
+        notes.add_symbol(&interface, "embeds", embeds);
+    }
+    for (member, from) in &source.promoted {
+        notes.add_symbol(member, "promoted_from", from.as_str());
+    }
+    notes
+}
+
+/// Set the analysis fields of every symbol, members included, from the
+/// parse of `source`. Fields behind a flag are only set when it is given.
+fn attach_analysis(
+    exports: &mut [OutlineExportJson],
+    entry: &FileEntry,
+    source: &OutlineSource,
+    options: &OutlineOptions,
+) {
+    let body_lines = source.body_lines(entry);
+    let const_blocks = source.const_blocks();
+    let embedded_fields = source.embedded_fields();
+    let embedded_interfaces = source.embedded_interfaces();
+    let external_receivers = source.external_receivers();
+    let analysis = |symbol: &str, metadata: &OutlineMetadataJson| {
+        let note = source
+            .symbol_field("deprecated", symbol)
+            .and_then(Value::as_str);
+        let error_return = source.symbol_field("error_returns", symbol);
+        OutlineAnalysisJson {
+            complexity: source
+                .symbol_field("complexity", symbol)
+                .and_then(Value::as_u64),
+            loc: body_lines.get(symbol).copied(),
+            embedded: embedded_fields.contains(symbol),
+            special: source
+                .symbol_field("special_functions", symbol)
+                .and_then(Value::as_str)
+                .map(str::to_string),
+            test: source.is_test,
+            test_kind: source
+                .symbol_field("test_kind", symbol)
+                .and_then(Value::as_str)
+                .map(str::to_string),
+            external_receiver: external_receivers
+                .get(symbol)
+                .filter(|_| metadata.kind.as_deref() == Some("method"))
+                .map(|receiver| receiver.to_string()),
+            embeds: embedded_interfaces.get(symbol).cloned().unwrap_or_default(),
+            promoted_from: source.promoted.get(symbol).cloned(),
+            block: const_blocks.get(symbol).copied(),
+            value: options
+                .eval_consts
+                .then(|| source.symbol_field("const_values", symbol))
+                .flatten()
+                .and_then(Value::as_str)
+                .map(str::to_string),
+            deprecated: note.is_some(),
+            deprecation_note: note.filter(|note| !note.is_empty()).map(str::to_string),
+            error_return: (source.is_go && is_callable(metadata)).then_some(error_return.is_some()),
+            error_return_position: error_return
+                .and_then(|error_return| error_return["position"].as_u64()),
+            ..Default::default()
+        }
+    };
+    for export in exports {
+        export.analysis = analysis(&export.name, &export.metadata);
+        for member in &mut export.members {
+            member.analysis = analysis(
+                &format!("{}.{}", export.name, member.name),
+                &member.metadata,
+            );
+        }
+    }
+}
+
+/// Move each `--markers` entry onto the listed function or method holding
+/// it, without its `function` key, and return the rest in source order.
+/// Extra tags need a parse of their own, which only Go supports.
+fn attach_markers(
+    exports: &mut [OutlineExportJson],
+    source: &OutlineSource,
+    extra_tags: &[String],
+) -> Vec<Value> {
+    let markers = if extra_tags.is_empty() || !source.is_go {
+        match source.fields.get("markers") {
+            Some(Value::Array(markers)) => markers.clone(),
+            _ => Vec::new(),
+        }
+    } else {
+        markers_with_tags(&source.text, extra_tags)
+    };
+
+    let mut by_function: HashMap<String, Vec<Value>> = HashMap::new();
+    let mut rest = Vec::new();
+    let listed: std::collections::HashSet<String> = exports
+        .iter()
+        .flat_map(|export| {
+            std::iter::once(export.name.clone()).chain(
+                export
+                    .members
+                    .iter()
+                    .map(|member| format!("{}.{}", export.name, member.name)),
+            )
+        })
+        .collect();
+    for mut marker in markers {
+        let function = marker
+            .get("function")
+            .and_then(Value::as_str)
+            .filter(|function| listed.contains(*function))
+            .map(str::to_string);
+        match (function, marker.as_object_mut()) {
+            (Some(function), Some(fields)) => {
+                fields.remove("function");
+                by_function.entry(function).or_default().push(marker);
+            }
+            _ => rest.push(marker),
+        }
+    }
+
+    for export in exports {
+        export.analysis.markers = by_function.remove(&export.name).unwrap_or_default();
+        for member in &mut export.members {
+            member.analysis.markers = by_function
+                .remove(&format!("{}.{}", export.name, member.name))
+                .unwrap_or_default();
+        }
+    }
+    rest
+}
+
+/// `--min-complexity`: drop functions and methods scoring below `min`, along
+/// with those the parser gave no score. Types and other symbols stay, holding
+/// whichever of their methods are left.
+fn retain_min_complexity(exports: &mut Vec<OutlineExportJson>, min: u64) {
+    let keep = |metadata: &OutlineMetadataJson, analysis: &OutlineAnalysisJson| {
+        !is_callable(metadata) || analysis.complexity.is_some_and(|score| score >= min)
+    };
+    exports.retain(|export| keep(&export.metadata, &export.analysis));
+    for export in exports {
+        export
+            .members
+            .retain(|member| keep(&member.metadata, &member.analysis));
+    }
+}
+
+/// Keep deprecated symbols only. A type that is not deprecated itself stays
+/// when one of its members is, listing just those members.
+fn retain_deprecated(exports: &mut Vec<OutlineExportJson>) {
+    for export in exports.iter_mut() {
+        export.members.retain(|member| member.analysis.deprecated);
+    }
+    exports.retain(|export| export.analysis.deprecated || !export.members.is_empty());
+}
+
+/// Set `snippet` on every function and method, members included.
+fn attach_snippets(exports: &mut [OutlineExportJson], source: &str, context: usize) {
+    let source_lines: Vec<&str> = source.lines().collect();
+    for export in exports {
+        if is_callable(&export.metadata) {
+            export.snippet = export
+                .lines
+                .and_then(|lines| body_snippet(&source_lines, lines, context));
+        }
+        for member in &mut export.members {
+            if is_callable(&member.metadata) {
+                member.snippet = body_snippet(&source_lines, member.lines, context);
+            }
+        }
+    }
+}
+
+/// Set `annotations` on every symbol from a single run of `command`. A command
+/// that fails or prints something other than the expected array only warns,
+/// so the outline is still printed, just unannotated.
+fn attach_annotations(exports: &mut [OutlineExportJson], command: &str) {
+    let names: Vec<String> = exports
+        .iter()
+        .flat_map(|export| {
+            std::iter::once(export.name.clone()).chain(
+                export
+                    .members
+                    .iter()
+                    .map(|member| format!("{}.{}", export.name, member.name)),
+            )
+        })
+        .collect();
+    if names.is_empty() {
+        return;
+    }
+    let annotations = match run_annotate_cmd(command, &names) {
+        Ok(annotations) => annotations,
+        Err(error) => {
+            eprintln!(
+                "{} --annotate-cmd: {error:#}; printing the outline without annotations",
+                "warning:".yellow()
+            );
+            return;
+        }
+    };
+    let mut annotations = annotations.into_iter();
+    for export in exports {
+        export.annotations = annotations.next().flatten();
+        for member in &mut export.members {
+            member.annotations = annotations.next().flatten();
+        }
+    }
+}
+
+/// Run `command` through the shell with `names` on stdin as one JSON array
+/// (`["Handler", "Handler.Start"]`). It must print an array of the same length
+/// holding an object, or `null` for no annotations, per name.
+fn run_annotate_cmd(command: &str, names: &[String]) -> Result<Vec<Option<Map<String, Value>>>> {
+    let (shell, flag) = if cfg!(windows) {
+        ("cmd", "/C")
+    } else {
+        ("sh", "-c")
+    };
+    let mut child = Command::new(shell)
+        .arg(flag)
+        .arg(command)
+        .stdin(Stdio::piped())
+        .stdout(Stdio::piped())
+        .spawn()
+        .with_context(|| format!("Failed to run `{command}`"))?;
+    let input = serde_json::to_vec(names)?;
+    let mut stdin = child.stdin.take().expect("stdin is piped");
+    // Written from a thread so a command that prints before it has read all
+    // of its input cannot deadlock on a full pipe. A command that ignores its
+    // input closes the pipe early, which is not an error in itself.
+    let writer = std::thread::spawn(move || stdin.write_all(&input));
+    let output = child
+        .wait_with_output()
+        .with_context(|| format!("Failed to run `{command}`"))?;
+    let _ = writer.join();
+
+    if !output.status.success() {
+        anyhow::bail!("`{command}` exited with {}", output.status);
+    }
+    let values: Vec<Value> = serde_json::from_slice(&output.stdout)
+        .with_context(|| format!("`{command}` did not print a JSON array"))?;
+    if values.len() != names.len() {
+        anyhow::bail!(
+            "`{command}` printed {} entries for {} symbols",
+            values.len(),
+            names.len()
+        );
+    }
+    values
+        .into_iter()
+        .zip(names)
+        .map(|(value, name)| match value {
+            Value::Object(annotations) => Ok(Some(annotations)),
+            Value::Null => Ok(None),
+            other => anyhow::bail!("`{command}` printed {other} for {name}, not an object"),
+        })
+        .collect()
+}
+
+fn is_callable(metadata: &OutlineMetadataJson) -> bool {
+    matches!(metadata.kind.as_deref(), Some("fn" | "method" | "test"))
+}
+
+/// The first `context` lines of the body of the declaration at `[start, end]`,
+/// verbatim, with a closing `…` line when the body is longer. The body runs
+/// from the line after the first one ending in `{` up to, not including, the
+/// last line. None for one-line and empty bodies.
+fn body_snippet(source_lines: &[&str], [start, end]: [usize; 2], context: usize) -> Option<String> {
+    let declaration = source_lines.get(start.checked_sub(1)?..end.min(source_lines.len()))?;
+    let open = declaration
+        .iter()
+        .position(|line| line.trim_end().ends_with('{'))?;
+    let body = declaration.get(open + 1..declaration.len() - 1)?;
+    if body.is_empty() || context == 0 {
+        return None;
+    }
+    let mut snippet = body[..body.len().min(context)].join("\n");
+    if body.len() > context {
+        snippet.push_str("\n…");
+    }
+    Some(snippet)
+}
+
+/// Terse outline for pasting into reviews: each export's signature on its own
+/// line, members indented under it, in source order. Symbols without a
+/// signature fall back to their name.
+fn print_outline_signatures(
+    entry: &FileEntry,
+    reexport_names: &std::collections::HashSet<&str>,
+    sort: OutlineSort,
+) {
+    for export in outline_exports(entry, reexport_names, sort) {
+        println!(
+            "{}",
+            compact_signature(export.metadata.signature.as_deref(), &export.name)
+        );
+        for member in export.members {
+            println!(
+                "  {}",
+                compact_signature(member.metadata.signature.as_deref(), &member.name)
+            );
+        }
+    }
+}
+
+/// Symbols as a box-drawn tree under the file, members as child branches.
+/// Type and function names are colored apart and symbols that are not public
+/// are dimmed. `colored` drops the styling when stdout is not a terminal or
+/// `NO_COLOR` is set.
+fn print_outline_tree(
+    file: &str,
+    entry: &FileEntry,
+    reexport_names: &std::collections::HashSet<&str>,
+    sort: OutlineSort,
+) {
+    println!("{}", file.bold());
+    let exports = outline_exports(entry, reexport_names, sort);
+    let export_count = exports.len();
+    for (i, export) in exports.into_iter().enumerate() {
+        let last = i + 1 == export_count;
+        println!(
+            "{}{}",
+            tree_branch(last),
+            tree_label(&export.name, &export.metadata, export.lines)
+        );
+        let indent = if last { "    " } else { "│   " };
+        let member_count = export.members.len();
+        for (j, member) in export.members.iter().enumerate() {
+            println!(
+                "{indent}{}{}",
+                tree_branch(j + 1 == member_count),
+                tree_label(&member.name, &member.metadata, Some(member.lines))
+            );
+        }
+    }
+}
+
+fn tree_branch(last: bool) -> &'static str {
+    if last { "└── " } else { "├── " }
+}
+
+/// `name kind [start, end]`, with the name styled by kind and visibility.
+fn tree_label(name: &str, metadata: &OutlineMetadataJson, lines: Option<[usize; 2]>) -> String {
+    let styled = match metadata.kind.as_deref() {
+        Some("struct" | "trait" | "enum" | "type" | "impl") => name.cyan().bold(),
+        Some("fn" | "method" | "test" | "macro") => name.green(),
+        _ => name.normal(),
+    };
+    let styled = match metadata.visibility.as_deref() {
+        Some(visibility) if visibility != "public" => styled.dimmed(),
+        _ => styled,
+    };
+    let mut label = styled.to_string();
+    if let Some(kind) = &metadata.kind {
+        label.push_str(&format!(" {}", kind.dimmed()));
+    }
+    if let Some([start, end]) = lines {
+        label.push_str(&format!(" [{start}, {end}]"));
+    }
+    label
+}
+
+/// `const`/`var` signatures keep their value only when every value is a simple
+/// literal; `const Timeout = 5 * time.Second` shortens to `const Timeout`.
+/// Values are split on top-level commas only, so `"a, b"` stays one string.
+fn compact_signature(signature: Option<&str>, name: &str) -> String {
+    let Some(signature) = signature else {
+        return name.to_string();
+    };
+    if !(signature.starts_with("const ") || signature.starts_with("var ")) {
+        return signature.to_string();
+    }
+    let Some((head, values)) = signature.split_once(" = ") else {
+        return signature.to_string();
+    };
+    match split_expression_list(values) {
+        Some(values) if values.iter().all(|value| is_simple_literal(value)) => {
+            signature.to_string()
+        }
+        _ => head.to_string(),
+    }
+}
+
+/// Number, string, rune and keyword literals, plus `iota`.
+fn is_simple_literal(value: &str) -> bool {
+    let value = value.trim();
+    let quoted = |quote: char| {
+        value.len() >= 2
+            && value.starts_with(quote)
+            && value.ends_with(quote)
+            && !value[1..value.len() - 1].contains(quote)
+    };
+    let number = value.strip_prefix('-').unwrap_or(value);
+    matches!(value, "true" | "false" | "nil" | "iota")
+        || quoted('"')
+        || quoted('`')
+        || quoted('\'')
+        || (number.starts_with(|c: char| c.is_ascii_digit())
+            && number
+                .chars()
+                .all(|c| c.is_ascii_alphanumeric() || c == '.' || c == '_'))
+}
+
+/// Local exports of `entry` with their outline metadata and members, ordered
+/// by source position so repeated runs diff cleanly, then by `sort`.
+fn outline_exports(
+    entry: &FileEntry,
+    reexport_names: &std::collections::HashSet<&str>,
+    sort: OutlineSort,
+) -> Vec<OutlineExportJson> {
+    let mut exports: Vec<OutlineExportJson> = entry
+        .exports
+        .iter()
+        .enumerate()
+        .filter(|(_, name)| !reexport_names.contains(name.as_str()))
+        .map(|(i, name)| {
+            let lines = entry
+                .export_lines
+                .as_ref()
+                .and_then(|el| el.get(i))
+                .filter(|l| l.start > 0)
+                .map(|l| [l.start, l.end]);
+            OutlineExportJson {
+                name: name.clone(),
+                lines,
+                size: lines.map(line_span),
+                metadata: entry
+                    .export_metadata
+                    .get(name)
+                    .map(OutlineMetadataJson::from)
+                    .unwrap_or_default(),
+                analysis: OutlineAnalysisJson::default(),
+                snippet: None,
+                annotations: None,
+                members: member_json(entry, name, sort),
+            }
+        })
+        .collect();
+    sort_by_position(&mut exports);
+    sort_outline_symbols(&mut exports, sort, |export| {
+        (
+            export.name.as_str(),
+            export.metadata.kind.as_deref(),
+            export.metadata.signature.as_deref(),
+        )
+    });
+    exports
+}
+
+/// Indexed members (`"Parent.member"` keys) of `parent`, in source order.
+fn member_json(entry: &FileEntry, parent: &str, sort: OutlineSort) -> Vec<OutlineMemberJson> {
+    let prefix = format!("{}.", parent);
+    let mut members: Vec<OutlineMemberJson> = entry
+        .methods
+        .iter()
+        .flatten()
+        .filter_map(|(dotted_name, lines)| {
+            let name = dotted_name.strip_prefix(&prefix)?;
+            Some(OutlineMemberJson {
+                name: name.to_string(),
+                lines: [lines.start, lines.end],
+                size: line_span([lines.start, lines.end]),
+                metadata: entry
+                    .method_metadata
+                    .get(dotted_name)
+                    .map(OutlineMetadataJson::from)
+                    .unwrap_or_default(),
+                analysis: OutlineAnalysisJson::default(),
+                snippet: None,
+                annotations: None,
+            })
+        })
+        .collect();
+    members.sort_by(|a, b| a.lines.cmp(&b.lines).then_with(|| a.name.cmp(&b.name)));
+    sort_outline_symbols(&mut members, sort, |member| {
+        (
+            member.name.as_str(),
+            member.metadata.kind.as_deref(),
+            member.metadata.signature.as_deref(),
+        )
+    });
+    members
+}
+
+fn outline_sort(sort: &str) -> OutlineSort {
+    match sort {
+        "name" => OutlineSort::Name,
+        "kind" => OutlineSort::Kind,
+        "godoc" => OutlineSort::Godoc,
+        _ => OutlineSort::Source,
+    }
+}
+
+/// Lines covered by `[start, end]`, as the YAML outline's `size:` row.
+fn line_span([start, end]: [usize; 2]) -> usize {
+    end.saturating_sub(start) + 1
+}
+
+/// Exports without a known line range keep their relative order at the end.
+fn sort_by_position(exports: &mut [OutlineExportJson]) {
+    exports.sort_by_key(|export| export.lines.map_or((1, [0, 0]), |lines| (0, lines)));
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
 
+    #[test]
+    fn compact_signature_keeps_simple_literals() {
+        assert_eq!(
+            compact_signature(Some("const MaxRetries = 3"), "MaxRetries"),
+            "const MaxRetries = 3"
+        );
+        assert_eq!(
+            compact_signature(Some("const StatusActive Status = iota"), "StatusActive"),
+            "const StatusActive Status = iota"
+        );
+        assert_eq!(
+            compact_signature(Some(r#"var Name, Addr = "fmm", ":8080""#), "Name"),
+            r#"var Name, Addr = "fmm", ":8080""#
+        );
+    }
+
+    #[test]
+    fn compact_signature_keeps_strings_containing_commas() {
+        assert_eq!(
+            compact_signature(Some(r#"const Sep = "a, b""#), "Sep"),
+            r#"const Sep = "a, b""#
+        );
+        assert_eq!(
+            compact_signature(Some(r#"var Sep, Max = ", ", max(1, 2)"#), "Sep"),
+            "var Sep, Max"
+        );
+    }
+
+    #[test]
+    fn compact_signature_elides_complex_values() {
+        assert_eq!(
+            compact_signature(Some("const Timeout = 5 * time.Second"), "Timeout"),
+            "const Timeout"
+        );
+        assert_eq!(
+            compact_signature(Some(r#"var ErrClosed = errors.New("closed")"#), "ErrClosed"),
+            "var ErrClosed"
+        );
+    }
+
+    #[test]
+    fn compact_signature_leaves_other_signatures_alone() {
+        assert_eq!(
+            compact_signature(Some("func (h *Handler) Start() error"), "Start"),
+            "func (h *Handler) Start() error"
+        );
+        assert_eq!(compact_signature(None, "main"), "main");
+    }
+
+    #[test]
+    fn body_snippet_keeps_leading_lines_verbatim() {
+        let source = "func (h *Handler) validate() error {\n\tif h.config.Host == \"\" {\n\t\treturn errors.New(\"host required\")\n\t}\n\treturn nil\n}";
+        let lines: Vec<&str> = source.lines().collect();
+        assert_eq!(
+            body_snippet(&lines, [1, 6], 1).as_deref(),
+            Some("\tif h.config.Host == \"\" {\n…")
+        );
+        assert_eq!(
+            body_snippet(&lines, [1, 6], 4).as_deref(),
+            Some(
+                "\tif h.config.Host == \"\" {\n\t\treturn errors.New(\"host required\")\n\t}\n\treturn nil"
+            )
+        );
+        assert_eq!(body_snippet(&["func noop() {}"], [1, 1], 3), None);
+    }
+
     #[test]
     fn outline_json_mixed_local_and_reexports() {
         let json = OutlineJson {
@@ -167,6 +1511,12 @@ mod tests {
             exports: vec![OutlineExportJson {
                 name: "main".to_string(),
                 lines: Some([83, 90]),
+                size: Some(8),
+                metadata: OutlineMetadataJson::default(),
+                analysis: OutlineAnalysisJson::default(),
+                snippet: None,
+                annotations: None,
+                members: vec![],
             }],
             reexports: vec![OutlineReExportJson {
                 name: "BindFailure".to_string(),
@@ -174,6 +1524,7 @@ mod tests {
                 origin_start: 12,
                 origin_end: 30,
             }],
+            markers: vec![],
             loc: 649,
         };
         // Round-trip through a dynamic Value so we don't hard-code the
@@ -202,8 +1553,15 @@ mod tests {
             exports: vec![OutlineExportJson {
                 name: "foo".to_string(),
                 lines: Some([1, 10]),
+                size: Some(10),
+                metadata: OutlineMetadataJson::default(),
+                analysis: OutlineAnalysisJson::default(),
+                snippet: None,
+                annotations: None,
+                members: vec![],
             }],
             reexports: vec![],
+            markers: vec![],
             loc: 20,
         };
         let s = serde_json::to_string(&json).unwrap();
@@ -227,12 +1585,72 @@ mod tests {
             exports: vec![OutlineExportJson {
                 name: "baz".to_string(),
                 lines: Some([2, 2]),
+                size: Some(1),
+                metadata: OutlineMetadataJson::default(),
+                analysis: OutlineAnalysisJson::default(),
+                snippet: None,
+                annotations: None,
+                members: vec![],
             }],
             reexports: vec![],
+            markers: vec![],
             loc: 5,
         };
         let s = serde_json::to_string(&json).unwrap();
         assert!(s.contains("\"baz\""));
         assert!(!s.contains("reexports"));
     }
+
+    #[test]
+    fn outline_json_exports_carry_metadata_and_members_in_source_order() {
+        let mut entry = FileEntry {
+            exports: vec!["Process".to_string(), "Handler".to_string()],
+            export_lines: Some(vec![
+                fmm_core::manifest::ExportLines { start: 40, end: 44 },
+                fmm_core::manifest::ExportLines { start: 10, end: 14 },
+            ]),
+            methods: Some(
+                [
+                    (
+                        "Handler.Serve".to_string(),
+                        fmm_core::manifest::ExportLines { start: 20, end: 22 },
+                    ),
+                    (
+                        "Handler.Close".to_string(),
+                        fmm_core::manifest::ExportLines { start: 16, end: 18 },
+                    ),
+                ]
+                .into_iter()
+                .collect(),
+            ),
+            ..Default::default()
+        };
+        entry.export_metadata.insert(
+            "Handler".to_string(),
+            SymbolMetadata {
+                signature: Some("type Handler struct".to_string()),
+                visibility: Some("public".to_string()),
+                declaration_kind: Some("struct".to_string()),
+            },
+        );
+
+        let exports = outline_exports(
+            &entry,
+            &std::collections::HashSet::new(),
+            OutlineSort::Source,
+        );
+
+        let v = serde_json::to_value(&exports).unwrap();
+        assert_eq!(v[0]["name"], "Handler");
+        assert_eq!(v[0]["signature"], "type Handler struct");
+        assert_eq!(v[0]["visibility"], "public");
+        assert_eq!(v[0]["kind"], "struct");
+        assert_eq!(v[0]["size"], 5);
+        assert_eq!(v[0]["members"][0]["name"], "Close");
+        assert_eq!(v[0]["members"][0]["size"], 3);
+        assert_eq!(v[0]["members"][1]["name"], "Serve");
+        assert_eq!(v[1]["name"], "Process");
+        assert!(v[1].get("signature").is_none());
+        assert!(v[1].get("members").is_none());
+    }
 }
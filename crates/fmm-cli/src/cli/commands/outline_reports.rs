@@ -0,0 +1,304 @@
+//! Report modes of `fmm outline`: each prints one analysis of the file in
+//! place of its outline, as text or, with `--json`, as JSON. The analyses
+//! come from a fresh parse, since the index does not keep them.
+
+use anyhow::Result;
+use serde_json::{Value, json};
+
+use super::outline::OutlineSource;
+
+/// The report an `fmm outline` flag selects; clap allows at most one.
+#[derive(Clone, Copy)]
+pub(super) enum OutlineReport {
+    /// `--callgraph`
+    Callgraph,
+    /// `--undocumented`, failing above `--max-undocumented`
+    Undocumented { max: usize },
+    /// `--receiver-report`
+    Receivers,
+    /// `--imports-only`
+    Imports,
+    /// `--layout`
+    Layout,
+    /// `--error-report`
+    Errors,
+    /// `--shadow-report`
+    Shadows,
+}
+
+impl OutlineReport {
+    pub(super) fn print(self, file: &str, source: &OutlineSource, json_output: bool) -> Result<()> {
+        match self {
+            Self::Callgraph => print_callgraph(file, source, json_output),
+            Self::Undocumented { max } => print_undocumented(file, source, max, json_output),
+            Self::Receivers => print_receivers(file, source, json_output),
+            Self::Imports => print_imports(file, source, json_output),
+            Self::Layout => print_layout(file, source, json_output),
+            Self::Errors => print_errors(file, source, json_output),
+            Self::Shadows => print_shadows(file, source, json_output),
+        }
+    }
+}
+
+/// `--callgraph`: the file's functions and methods as nodes, with an edge
+/// for each call the parser resolved statically. Text output is Graphviz
+/// DOT, so `fmm outline main.go --callgraph | dot -Tsvg` draws it.
+fn print_callgraph(file: &str, source: &OutlineSource, json_output: bool) -> Result<()> {
+    let nodes: Vec<&str> = field_entries(source, "body_lines")
+        .map(|(name, _)| name.as_str())
+        .collect();
+    let edges: Vec<(&str, &str)> = field_entries(source, "calls")
+        .flat_map(|(caller, callees)| {
+            callees
+                .as_array()
+                .into_iter()
+                .flatten()
+                .filter_map(Value::as_str)
+                .map(move |callee| (caller.as_str(), callee))
+        })
+        .collect();
+
+    if json_output {
+        let edges: Vec<Value> = edges
+            .iter()
+            .map(|(from, to)| json!({ "from": from, "to": to }))
+            .collect();
+        let report = json!({ "file": file, "nodes": nodes, "edges": edges });
+        println!("{}", serde_json::to_string_pretty(&report)?);
+        return Ok(());
+    }
+
+    println!("digraph {} {{", dot_id(file));
+    for node in &nodes {
+        println!("    {};", dot_id(node));
+    }
+    for (from, to) in &edges {
+        println!("    {} -> {};", dot_id(from), dot_id(to));
+    }
+    println!("}}");
+    Ok(())
+}
+
+/// `--undocumented`: exported symbols without a doc comment, one per line,
+/// and an error once there are more than `max`, so CI can gate on it.
+fn print_undocumented(
+    file: &str,
+    source: &OutlineSource,
+    max: usize,
+    json_output: bool,
+) -> Result<()> {
+    let names: Vec<&str> = field_items(source, "undocumented")
+        .filter_map(Value::as_str)
+        .collect();
+
+    if json_output {
+        let report = json!({ "file": file, "undocumented": names });
+        println!("{}", serde_json::to_string_pretty(&report)?);
+    } else {
+        for name in &names {
+            println!("{name}");
+        }
+    }
+
+    if names.len() > max {
+        anyhow::bail!(
+            "{file}: {} undocumented exported symbols, more than --max-undocumented {max}",
+            names.len()
+        );
+    }
+    Ok(())
+}
+
+/// `--receiver-report`: receiver types whose methods mix pointer and value
+/// receivers or receiver names, each followed by its methods and the
+/// receiver each one declares.
+fn print_receivers(file: &str, source: &OutlineSource, json_output: bool) -> Result<()> {
+    if json_output {
+        let receivers = source
+            .fields
+            .get("inconsistent_receivers")
+            .cloned()
+            .unwrap_or_else(|| json!({}));
+        let report = json!({ "file": file, "inconsistent_receivers": receivers });
+        println!("{}", serde_json::to_string_pretty(&report)?);
+        return Ok(());
+    }
+
+    for (receiver, methods) in field_entries(source, "inconsistent_receivers") {
+        println!("{receiver}");
+        for (method, declared) in methods.as_object().into_iter().flatten() {
+            println!("  {method}: {}", declared.as_str().unwrap_or_default());
+        }
+    }
+    Ok(())
+}
+
+/// `--imports-only`: the file's imports in path order, each with its kind
+/// (`stdlib`, `third_party` or `module`) and, when the package name is
+/// never referenced, `unused`.
+fn print_imports(file: &str, source: &OutlineSource, json_output: bool) -> Result<()> {
+    let unused: Vec<&str> = field_items(source, "unused_imports")
+        .filter_map(Value::as_str)
+        .collect();
+    let imports: Vec<(&str, &str, bool)> = field_entries(source, "import_kinds")
+        .map(|(path, kind)| {
+            let used = !unused.contains(&path.as_str());
+            (path.as_str(), kind.as_str().unwrap_or_default(), used)
+        })
+        .collect();
+
+    if json_output {
+        let imports: Vec<Value> = imports
+            .iter()
+            .map(|(path, kind, used)| json!({ "path": path, "kind": kind, "used": used }))
+            .collect();
+        let report = json!({ "file": file, "imports": imports });
+        println!("{}", serde_json::to_string_pretty(&report)?);
+        return Ok(());
+    }
+
+    for (path, kind, used) in &imports {
+        if *used {
+            println!("{path} {kind}");
+        } else {
+            println!("{path} {kind} unused");
+        }
+    }
+    Ok(())
+}
+
+/// `--layout`: each struct's estimated size and alignment, then its fields
+/// with their offset, size and any padding after them, and the smaller field
+/// order when there is one. Structs holding a field of unknown size name
+/// those fields instead.
+fn print_layout(file: &str, source: &OutlineSource, json_output: bool) -> Result<()> {
+    if json_output {
+        let layouts = source
+            .fields
+            .get("struct_layout")
+            .cloned()
+            .unwrap_or_else(|| json!({}));
+        let report = json!({ "file": file, "struct_layout": layouts });
+        println!("{}", serde_json::to_string_pretty(&report)?);
+        return Ok(());
+    }
+
+    let names = |layout: &Value, key: &str| -> String {
+        let names: Vec<&str> = layout[key]
+            .as_array()
+            .into_iter()
+            .flatten()
+            .filter_map(Value::as_str)
+            .collect();
+        names.join(", ")
+    };
+    for (name, layout) in field_entries(source, "struct_layout") {
+        if !layout["size"].is_u64() {
+            println!(
+                "{name}: size unknown because of {}",
+                names(layout, "unknown_fields")
+            );
+            continue;
+        }
+        println!("{name}: size {}, align {}", layout["size"], layout["align"]);
+        for field in layout["fields"].as_array().into_iter().flatten() {
+            let padding = match field.get("padding") {
+                Some(padding) => format!(", padding {padding}"),
+                None => String::new(),
+            };
+            println!(
+                "  {}: offset {}, size {}{padding}",
+                field["name"].as_str().unwrap_or_default(),
+                field["offset"],
+                field["size"]
+            );
+        }
+        if layout.get("suggested_order").is_some() {
+            println!(
+                "  suggested order: {} (size {})",
+                names(layout, "suggested_order"),
+                layout["suggested_size"]
+            );
+        }
+    }
+    Ok(())
+}
+
+/// `--error-report`: the functions and methods with the built-in `error`
+/// among their results, each with the slot of the first `error` and whether
+/// the final result is `error`, as Go convention expects.
+fn print_errors(file: &str, source: &OutlineSource, json_output: bool) -> Result<()> {
+    if json_output {
+        let returns = source
+            .fields
+            .get("error_returns")
+            .cloned()
+            .unwrap_or_else(|| json!({}));
+        let report = json!({ "file": file, "error_returns": returns });
+        println!("{}", serde_json::to_string_pretty(&report)?);
+        return Ok(());
+    }
+
+    for (name, error_return) in field_entries(source, "error_returns") {
+        let last = if error_return["last"] == true {
+            "last"
+        } else {
+            "not last"
+        };
+        println!("{name}: result {} ({last})", error_return["position"]);
+    }
+    Ok(())
+}
+
+/// `--shadow-report`: local names that shadow a package-level name declared
+/// in the file, in source order, with the line of each declaration.
+fn print_shadows(file: &str, source: &OutlineSource, json_output: bool) -> Result<()> {
+    let shadowed: Vec<&Value> = field_items(source, "shadowed").collect();
+
+    if json_output {
+        let report = json!({ "file": file, "shadowed": shadowed });
+        println!("{}", serde_json::to_string_pretty(&report)?);
+        return Ok(());
+    }
+
+    for shadow in &shadowed {
+        println!(
+            "{}: {} at line {} shadows line {}",
+            shadow["function"].as_str().unwrap_or_default(),
+            shadow["name"].as_str().unwrap_or_default(),
+            shadow["line"],
+            shadow["declared_line"]
+        );
+    }
+    Ok(())
+}
+
+/// Items of the array-valued field `key`; none when the parser did not
+/// record it.
+fn field_items<'a>(source: &'a OutlineSource, key: &str) -> impl Iterator<Item = &'a Value> {
+    source
+        .fields
+        .get(key)
+        .and_then(Value::as_array)
+        .into_iter()
+        .flatten()
+}
+
+/// Entries of the object-valued field `key`; none when the parser did not
+/// record it.
+fn field_entries<'a>(
+    source: &'a OutlineSource,
+    key: &str,
+) -> impl Iterator<Item = (&'a String, &'a Value)> {
+    source
+        .fields
+        .get(key)
+        .and_then(Value::as_object)
+        .into_iter()
+        .flatten()
+}
+
+/// A quoted DOT identifier.
+fn dot_id(id: &str) -> String {
+    format!("\"{}\"", id.replace('\\', "\\\\").replace('"', "\\\""))
+}
@@ -0,0 +1,8 @@
+use clap::Args;
+
+#[derive(Args)]
+pub struct VersionCommandArgs {
+    /// Output as JSON
+    #[arg(short = 'j', long = "json")]
+    pub json: bool,
+}
@@ -4,11 +4,28 @@ use std::path::{Path, PathBuf};
 
 use fmm_core::config::Config;
 
+/// Directories the Go toolchain never builds from. Go files below them are
+/// fixtures or vendored copies, not part of the package being indexed.
+const GO_SKIPPED_DIRS: &[&str] = &["vendor", "testdata"];
+
 /// Returns `(kept_files, skipped_count)`.
 ///
 /// Files exceeding `config.max_lines` are excluded from `kept_files` and
 /// counted in `skipped_count`. The caller is responsible for reporting skips.
 pub(crate) fn collect_files(path: &str, config: &Config) -> Result<(Vec<PathBuf>, usize)> {
+    collect_files_with_depth(path, config, true)
+}
+
+/// Like [`collect_files`]; with `recurse` false only the files directly in a
+/// directory argument are collected.
+///
+/// `.gitignore` rules apply even outside a git checkout, and symlinked
+/// directories are never followed, so link cycles cannot loop the walk.
+pub(crate) fn collect_files_with_depth(
+    path: &str,
+    config: &Config,
+    recurse: bool,
+) -> Result<(Vec<PathBuf>, usize)> {
     let path = Path::new(path);
 
     if path.is_file() {
@@ -19,19 +36,18 @@ pub(crate) fn collect_files(path: &str, config: &Config) -> Result<(Vec<PathBuf>
         return Ok((vec![canonical], 0));
     }
 
-    // Pre-compile exclude glob patterns (relative to the walk root).
-    let exclude_patterns: Vec<glob::Pattern> = config
-        .exclude
-        .iter()
-        .filter_map(|p| glob::Pattern::new(p).ok())
-        .collect();
+    let exclude_patterns = compile_patterns(&config.exclude);
+    let include_patterns = compile_patterns(&config.include);
 
     let candidates: Vec<PathBuf> = WalkBuilder::new(path)
         .standard_filters(true)
+        .require_git(false)
+        .max_depth((!recurse).then_some(1))
         .add_custom_ignore_filename(".fmmignore")
         .build()
         .filter_map(|entry| entry.ok())
         .filter(|entry| entry.file_type().is_some_and(|ft| ft.is_file()))
+        .filter(|entry| !is_skipped_go_file(entry.path(), path))
         .filter(|entry| {
             if let Some(ext) = entry.path().extension() {
                 config.is_supported_language(ext.to_str().unwrap_or(""))
@@ -40,22 +56,9 @@ pub(crate) fn collect_files(path: &str, config: &Config) -> Result<(Vec<PathBuf>
             }
         })
         .filter(|entry| {
-            if exclude_patterns.is_empty() {
-                return true;
-            }
-            // Normalize the entry path: strip leading "./" so patterns like
-            // "vendor/**" match both "vendor/foo.js" and "./vendor/foo.js".
-            let raw = entry.path().to_string_lossy();
-            let rel = raw.strip_prefix("./").unwrap_or(&raw);
-            !exclude_patterns.iter().any(|pat| {
-                pat.matches_with(
-                    rel,
-                    glob::MatchOptions {
-                        require_literal_separator: false,
-                        ..Default::default()
-                    },
-                )
-            })
+            !matches_any(&exclude_patterns, entry.path(), path)
+                && (include_patterns.is_empty()
+                    || matches_any(&include_patterns, entry.path(), path))
         })
         .map(|entry| {
             entry
@@ -76,6 +79,63 @@ pub(crate) fn collect_files(path: &str, config: &Config) -> Result<(Vec<PathBuf>
     Ok((files, skip_count))
 }
 
+/// Compiles glob patterns; invalid patterns are skipped.
+fn compile_patterns(patterns: &[String]) -> Vec<glob::Pattern> {
+    patterns
+        .iter()
+        .filter_map(|p| glob::Pattern::new(p).ok())
+        .collect()
+}
+
+/// Whether any pattern matches `file`, written either as walked (without a
+/// leading `./`) or relative to the walk root, so `internal/**` matches
+/// whichever directory fmm was pointed at. `*` crosses `/` and `**` matches
+/// any depth.
+fn matches_any(patterns: &[glob::Pattern], file: &Path, walk_root: &Path) -> bool {
+    if patterns.is_empty() {
+        return false;
+    }
+    let raw = file.to_string_lossy();
+    let walked = raw.strip_prefix("./").unwrap_or(&raw);
+    let relative = file
+        .strip_prefix(walk_root)
+        .ok()
+        .map(|rel| rel.to_string_lossy());
+    let options = glob::MatchOptions {
+        require_literal_separator: false,
+        ..Default::default()
+    };
+    patterns.iter().any(|pat| {
+        pat.matches_with(walked, options)
+            || relative
+                .as_deref()
+                .is_some_and(|rel| pat.matches_with(rel, options))
+    })
+}
+
+/// True for a `.go` file below a `vendor/` or `testdata/` directory of the
+/// walk root. Shared with the watcher so it never indexes what a full walk
+/// skips.
+///
+/// Only directories below `walk_root` count: a checkout that itself lives
+/// under some `vendor/` is still indexed, and a file outside the root is
+/// never skipped on this basis.
+pub(crate) fn is_skipped_go_file(file: &Path, walk_root: &Path) -> bool {
+    if file.extension().and_then(|ext| ext.to_str()) != Some("go") {
+        return false;
+    }
+    let Ok(rel) = file.strip_prefix(walk_root) else {
+        return false;
+    };
+    rel.parent().is_some_and(|dir| {
+        dir.components().any(|component| {
+            GO_SKIPPED_DIRS
+                .iter()
+                .any(|skipped| component.as_os_str() == *skipped)
+        })
+    })
+}
+
 /// Returns true if the file has at most `max_lines` lines.
 ///
 /// Uses a byte-size lower bound to avoid reading most files: any file with
@@ -123,11 +183,12 @@ fn bytecount_newlines(bytes: &[u8]) -> usize {
 pub(crate) fn collect_files_multi(
     paths: &[String],
     config: &Config,
+    recurse: bool,
 ) -> Result<(Vec<PathBuf>, usize)> {
     let mut all_files = Vec::new();
     let mut total_skipped = 0usize;
     for path in paths {
-        let (files, skipped) = collect_files(path, config)?;
+        let (files, skipped) = collect_files_with_depth(path, config, recurse)?;
         all_files.extend(files);
         total_skipped += skipped;
     }
@@ -240,4 +301,102 @@ mod tests {
         assert_eq!(files.len(), 1);
         assert_eq!(skipped, 0);
     }
+
+    // collect_files directory walking
+
+    #[test]
+    fn collect_files_skips_go_vendor_and_testdata() {
+        let tmp = TempDir::new().unwrap();
+        for rel in [
+            "main.go",
+            "pkg/server/server.go",
+            "vendor/github.com/lib/lib.go",
+            "pkg/server/testdata/broken.go",
+        ] {
+            let path = tmp.path().join(rel);
+            std::fs::create_dir_all(path.parent().unwrap()).unwrap();
+            std::fs::write(path, "package x\n").unwrap();
+        }
+
+        let config = Config::default();
+        let (files, _) = collect_files(tmp.path().to_str().unwrap(), &config).unwrap();
+        let names: Vec<String> = files
+            .iter()
+            .map(|f| f.file_name().unwrap().to_string_lossy().into_owned())
+            .collect();
+
+        assert_eq!(files.len(), 2, "got: {:?}", names);
+        assert!(names.contains(&"main.go".to_string()));
+        assert!(names.contains(&"server.go".to_string()));
+    }
+
+    #[test]
+    fn collect_files_keeps_go_files_when_walk_root_is_under_vendor() {
+        let tmp = TempDir::new().unwrap();
+        let root = tmp.path().join("vendor/github.com/org/proj");
+        for rel in ["main.go", "pkg/server/server.go", "testdata/fixture.go"] {
+            let path = root.join(rel);
+            std::fs::create_dir_all(path.parent().unwrap()).unwrap();
+            std::fs::write(path, "package x\n").unwrap();
+        }
+
+        let config = Config::default();
+        let (files, _) = collect_files(root.to_str().unwrap(), &config).unwrap();
+        let mut names: Vec<String> = files
+            .iter()
+            .map(|f| f.file_name().unwrap().to_string_lossy().into_owned())
+            .collect();
+        names.sort();
+
+        assert_eq!(names, vec!["main.go", "server.go"]);
+    }
+
+    #[test]
+    fn is_skipped_go_file_ignores_paths_outside_walk_root() {
+        let root = Path::new("/work/proj");
+        assert!(is_skipped_go_file(
+            Path::new("/work/proj/vendor/lib/lib.go"),
+            root
+        ));
+        assert!(!is_skipped_go_file(
+            Path::new("/vendor/other/main.go"),
+            root
+        ));
+    }
+
+    #[test]
+    fn collect_files_honors_gitignore_outside_git_checkout() {
+        let tmp = TempDir::new().unwrap();
+        std::fs::write(tmp.path().join(".gitignore"), "*.pb.go\n!keep.pb.go\n").unwrap();
+        for name in ["main.go", "api.pb.go", "keep.pb.go"] {
+            std::fs::write(tmp.path().join(name), "package main\n").unwrap();
+        }
+
+        let config = Config::default();
+        let (files, _) = collect_files(tmp.path().to_str().unwrap(), &config).unwrap();
+        let mut names: Vec<String> = files
+            .iter()
+            .map(|f| f.file_name().unwrap().to_string_lossy().into_owned())
+            .collect();
+        names.sort();
+
+        assert_eq!(names, vec!["keep.pb.go", "main.go"]);
+    }
+
+    #[test]
+    fn collect_files_without_recurse_stays_in_directory() {
+        let tmp = TempDir::new().unwrap();
+        std::fs::create_dir_all(tmp.path().join("sub")).unwrap();
+        std::fs::write(tmp.path().join("main.go"), "package main\n").unwrap();
+        std::fs::write(tmp.path().join("sub/sub.go"), "package sub\n").unwrap();
+
+        let config = Config::default();
+        let root = tmp.path().to_str().unwrap();
+        let (shallow, _) = collect_files_with_depth(root, &config, false).unwrap();
+        let (deep, _) = collect_files(root, &config).unwrap();
+
+        assert_eq!(shallow.len(), 1);
+        assert!(shallow[0].ends_with("main.go"));
+        assert_eq!(deep.len(), 2);
+    }
 }
@@ -0,0 +1,107 @@
+use clap::Command;
+use fmm_core::config::{Config, FlagDefault};
+
+/// `command` with the `[defaults.<command>]` entries of `config` set as the
+/// defaults of its subcommands' flags, so `--help` shows them and a flag
+/// given on the command line still wins. Only flags that take a value can
+/// have a default: a switch set by the config could not be turned off again.
+/// Entries naming no subcommand, no such flag or a switch are left out and
+/// returned as `defaults.<command>` or `defaults.<command>.<flag>`.
+pub fn with_flag_defaults(mut command: Command, config: &Config) -> (Command, Vec<String>) {
+    let mut ignored = Vec::new();
+    for (name, flags) in &config.defaults {
+        let Some(name) = command
+            .find_subcommand(name)
+            .map(|subcommand| subcommand.get_name().to_string())
+        else {
+            ignored.push(format!("defaults.{name}"));
+            continue;
+        };
+        command = command.mut_subcommand(&name, |mut subcommand| {
+            for (flag, value) in flags {
+                let Some(id) = value_flag_id(&subcommand, flag) else {
+                    ignored.push(format!("defaults.{name}.{flag}"));
+                    continue;
+                };
+                subcommand = subcommand.mut_arg(id, |arg| match value {
+                    FlagDefault::One(value) => arg.default_value(value.clone()),
+                    FlagDefault::Many(values) => arg.default_values(values.clone()),
+                });
+            }
+            subcommand
+        });
+    }
+    (command, ignored)
+}
+
+/// Id of the flag of `command` named `--<long>`, when it takes a value.
+fn value_flag_id(command: &Command, long: &str) -> Option<String> {
+    command
+        .get_arguments()
+        .find(|arg| arg.get_long() == Some(long))
+        .filter(|arg| arg.get_action().takes_values())
+        .map(|arg| arg.get_id().to_string())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use clap::{Arg, ArgAction};
+    use std::collections::BTreeMap;
+
+    fn outline() -> Command {
+        Command::new("fmm").subcommand(
+            Command::new("outline")
+                .arg(Arg::new("sort").long("sort").default_value("source"))
+                .arg(Arg::new("json").long("json").action(ArgAction::SetTrue)),
+        )
+    }
+
+    fn config(flags: &[(&str, &str, FlagDefault)]) -> Config {
+        let mut defaults: BTreeMap<String, BTreeMap<String, FlagDefault>> = BTreeMap::new();
+        for (command, flag, value) in flags {
+            defaults
+                .entry(command.to_string())
+                .or_default()
+                .insert(flag.to_string(), value.clone());
+        }
+        Config {
+            defaults,
+            ..Config::default()
+        }
+    }
+
+    fn sort(command: Command, args: &[&str]) -> String {
+        let matches = command.try_get_matches_from(args).unwrap();
+        let outline = matches.subcommand_matches("outline").unwrap();
+        outline.get_one::<String>("sort").unwrap().clone()
+    }
+
+    #[test]
+    fn configured_default_applies_and_the_command_line_wins() {
+        let config = config(&[("outline", "sort", FlagDefault::One("name".into()))]);
+        let (command, ignored) = with_flag_defaults(outline(), &config);
+        assert!(ignored.is_empty());
+        assert_eq!(sort(command.clone(), &["fmm", "outline"]), "name");
+        assert_eq!(sort(command, &["fmm", "outline", "--sort", "kind"]), "kind");
+    }
+
+    #[test]
+    fn unknown_commands_flags_and_switches_are_ignored_by_name() {
+        let config = config(&[
+            ("outlin", "sort", FlagDefault::One("name".into())),
+            ("outline", "sorting", FlagDefault::One("name".into())),
+            ("outline", "json", FlagDefault::One("true".into())),
+        ]);
+        let (command, ignored) = with_flag_defaults(outline(), &config);
+        assert_eq!(
+            ignored,
+            vec![
+                "defaults.outlin",
+                "defaults.outline.json",
+                "defaults.outline.sorting"
+            ]
+        );
+        assert_eq!(sort(command, &["fmm", "outline"]), "source");
+    }
+}
@@ -23,6 +23,7 @@ pub const SHORT_HELP: &str = cstr!(
   <bold>validate</bold>      Check the index is current (CI-friendly, exit 1 if stale)
   <bold>mcp</bold>           Start MCP server (10 tools for LLM navigation)
   <bold>status</bold>        Show config and workspace stats
+  <bold>version</bold>       Show version, languages, and feature names
   <bold>clean</bold>         Clear the fmm index database
 
 Use <bold>--help</bold> for workflows and examples.
@@ -49,6 +50,7 @@ pub const LONG_HELP: &str = cstr!(
   <bold>validate</bold>      Check the index is current (CI-friendly, exit 1 if stale)
   <bold>mcp</bold>           Start MCP server (10 tools for LLM navigation)
   <bold>status</bold>        Show config and workspace stats
+  <bold>version</bold>       Show version, languages, and feature names
   <bold>clean</bold>         Clear the fmm index database
 
 <bold><underline>Core Workflow</underline></bold>
@@ -110,6 +110,7 @@ const FMMRC_TEMPLATE: &str = r#"# fmm configuration
 #   FMM_MAX_LINES=50000
 #   FMM_LANGUAGES=rs,py,ts
 #   FMM_EXCLUDE=vendor/**,dist/**
+#   FMM_INCLUDE=cmd/**,internal/**
 #
 # Note: .gitignore and .fmmignore are always respected. Exclude patterns here
 # are applied in addition to those ignore files.
@@ -121,6 +122,10 @@ const FMMRC_TEMPLATE: &str = r#"# fmm configuration
 # Glob patterns to exclude (in addition to .gitignore and .fmmignore).
 # exclude = ["benchmarks/fixtures/**", "vendor/**"]
 
+# Glob patterns to index exclusively (default: everything not excluded).
+# Exclude patterns win over include patterns.
+# include = ["cmd/**", "internal/**"]
+
 # Override which file extensions to index (default: 29 languages).
 # languages = ["ts", "tsx", "js", "jsx", "py", "rs"]
 
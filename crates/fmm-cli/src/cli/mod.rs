@@ -4,17 +4,19 @@ use std::path::PathBuf;
 mod command_tree;
 mod commands;
 mod files;
+mod flag_defaults;
 mod glossary;
 pub mod init;
 mod resolve;
 mod search;
 pub(crate) mod sidecar;
 mod status;
+mod version;
 mod watch;
 
 // Re-export file/resolve utilities so sibling modules (sidecar, init, watch, status)
 // can continue using `super::collect_files`, `super::resolve_root`, etc.
-pub(crate) use files::{collect_files, collect_files_multi};
+pub(crate) use files::{collect_files, collect_files_multi, is_skipped_go_file};
 pub(crate) use resolve::{resolve_root, resolve_root_multi};
 
 mod help_text;
@@ -25,16 +27,21 @@ mod generated_help;
 pub use command_tree::Commands;
 pub use commands::{
     CleanCommandArgs, CompletionsCommandArgs, CyclesCommandArgs, DepsCommandArgs, DupesCommandArgs,
-    ExportsCommandArgs, GenerateCommandArgs, GlossaryCommandArgs, InitCommandArgs,
-    LookupCommandArgs, LsCommandArgs, OutlineCommandArgs, ReadCommandArgs, SearchCommandArgs,
-    SimilarCommandArgs, ValidateCommandArgs, WatchCommandArgs, cycles, deps, dupes, exports,
-    lookup, ls, outline, read_symbol, similar,
+    ExportsCommandArgs, ExportsOptions, GenerateCommandArgs, GlossaryCommandArgs, InitCommandArgs,
+    LookupCommandArgs, LsCommandArgs, OutlineCommandArgs, OutlineOptions, ReadCommandArgs,
+    SearchCommandArgs, SimilarCommandArgs, ValidateCommandArgs, VersionCommandArgs,
+    WatchCommandArgs, cycles, deps, dupes, exports, lookup, ls, outline, read_symbol, similar,
 };
+pub use flag_defaults::with_flag_defaults;
 pub use glossary::glossary;
 pub use init::init;
 pub use search::{SearchOptions, search};
-pub use sidecar::{clean, generate, generate_with_git, validate};
+pub use sidecar::{
+    GenerateError, GenerateOptions, GenerateSummary, clean, generate, generate_with_options,
+    validate,
+};
 pub use status::status;
+pub use version::version;
 pub use watch::watch;
 
 #[derive(Parser)]
@@ -15,50 +15,131 @@ use fmm_core::resolver;
 use super::{collect_files_multi, resolve_root_multi};
 
 mod output;
+mod packages;
 mod parse;
 pub(crate) mod staleness;
+mod stats;
 
 use output::{
     print_all_up_to_date, print_dry_run_summary, print_files_summary, print_no_supported_files,
-    print_phase_timings, run_with_spinner, start_spinner,
+    print_parse_failures, print_phase_timings, print_unknown_config_keys, run_with_spinner,
+    start_spinner,
 };
+pub(crate) use packages::read_go_package;
 
 /// Show progress bars when at least this many files need processing.
 const PROGRESS_THRESHOLD: usize = 10;
 
-pub fn generate(paths: &[String], dry_run: bool, force: bool, quiet: bool) -> Result<()> {
-    generate_with_git(paths, dry_run, force, quiet, None, false)
+/// Everything `fmm generate` takes besides the paths, one field per flag.
+#[derive(Default)]
+pub struct GenerateOptions {
+    pub dry_run: bool,
+    pub force: bool,
+    pub quiet: bool,
+    /// `--sha`: the git SHA to stamp instead of the probed one.
+    pub sha_override: Option<String>,
+    pub no_git: bool,
+    pub no_recurse: bool,
+    pub fail_on_parse_error: bool,
+    pub stats: bool,
+    pub include: Vec<String>,
+    pub exclude: Vec<String>,
+    /// `--json`: print the [`GenerateSummary`] as JSON on stdout instead of
+    /// the progress and summary lines.
+    pub json: bool,
 }
 
-pub fn generate_with_git(
+/// What a `generate` run did. Parse failures do not fail the run; the caller
+/// decides what they mean (`fmm generate` exits non-zero, watch mode carries on).
+#[derive(Debug, Default, serde::Serialize)]
+pub struct GenerateSummary {
+    /// Files written to the index by this run.
+    pub indexed: usize,
+    /// Files that failed to parse and were left out of the index.
+    pub errors: Vec<GenerateError>,
+}
+
+#[derive(Debug, serde::Serialize)]
+pub struct GenerateError {
+    /// Path relative to the index root.
+    pub file: String,
+    pub error: String,
+}
+
+impl GenerateSummary {
+    fn new(indexed: usize, failures: &[parse::ParseFailure], root: &Path) -> Self {
+        let errors = failures
+            .iter()
+            .map(|(path, error)| GenerateError {
+                file: path
+                    .strip_prefix(root)
+                    .unwrap_or(path)
+                    .display()
+                    .to_string(),
+                error: error.clone(),
+            })
+            .collect();
+        Self { indexed, errors }
+    }
+}
+
+pub fn generate(
     paths: &[String],
     dry_run: bool,
     force: bool,
     quiet: bool,
-    sha_override: Option<&str>,
-    no_git: bool,
-) -> Result<()> {
+) -> Result<GenerateSummary> {
+    generate_with_options(
+        paths,
+        &GenerateOptions {
+            dry_run,
+            force,
+            quiet,
+            ..Default::default()
+        },
+    )
+}
+
+/// Index `paths` into `.fmm.db`. Files that fail to parse are reported on
+/// stderr, the rest are indexed anyway, and the failures are returned in the
+/// summary. With `fail_on_parse_error` any failure aborts the run before the
+/// database is written. `stats` prints a size summary of the indexed files to
+/// stderr. `include` and `exclude` globs are added to the configured ones.
+pub fn generate_with_options(
+    paths: &[String],
+    options: &GenerateOptions,
+) -> Result<GenerateSummary> {
+    let &GenerateOptions { force, json, .. } = options;
+    // JSON output replaces the human-readable lines on stdout.
+    let quiet = options.quiet || json;
     let total_start = Instant::now();
-    let config = Config::load().unwrap_or_default();
+    let mut config = Config::load().unwrap_or_default();
+    if !quiet {
+        print_unknown_config_keys(&config);
+    }
+    config.include.extend_from_slice(&options.include);
+    config.exclude.extend_from_slice(&options.exclude);
 
     let scan_sp = (!quiet).then(|| start_spinner("{spinner:.blue} Scanning files..."));
-    let (files, skipped) = collect_files_multi(paths, &config)?;
+    let (files, skipped) = collect_files_multi(paths, &config, !options.no_recurse)?;
     let root = resolve_root_multi(paths)?;
     if let Some(sp) = &scan_sp {
         sp.finish_and_clear();
     }
 
     if files.is_empty() {
-        print_no_supported_files(&config);
-        return Ok(());
+        if !json {
+            print_no_supported_files(&config);
+        }
+        return finish(GenerateSummary::default(), json);
     }
 
-    if dry_run {
+    if options.dry_run {
         print_dry_run_summary(&files, &root, force)?;
-        return Ok(());
+        return Ok(GenerateSummary::default());
     }
 
-    let git_meta = crate::git::probe(&root, sha_override, no_git)?;
+    let git_meta = crate::git::probe(&root, options.sha_override.as_deref(), options.no_git)?;
     let store = SqliteStore::open_or_create(&root)?;
     let workspace_info = resolver::workspace::discover(&root);
     store.upsert_workspace_packages(&workspace_info.packages)?;
@@ -79,14 +160,19 @@ pub fn generate_with_git(
                 git_meta.as_ref(),
                 total_start,
                 scan.elapsed,
-                quiet,
+                options,
                 removed_count,
             )?;
         } else {
-            print_all_up_to_date(files.len() + skipped, skipped, total_start.elapsed());
+            if !json {
+                print_all_up_to_date(files.len() + skipped, skipped, total_start.elapsed());
+            }
             store.write_meta(git_meta.as_ref())?;
         }
-        return Ok(());
+        if options.stats {
+            print_index_stats(&store, &files, &root)?;
+        }
+        return finish(GenerateSummary::default(), json);
     }
 
     let show_progress = !quiet && scan.dirty_files.len() >= PROGRESS_THRESHOLD;
@@ -102,7 +188,18 @@ pub fn generate_with_git(
         .iter()
         .map(|file| (file.path.as_path(), file.fingerprint.clone()))
         .collect();
-    let (parse_results, phase2_elapsed) = parse::parse_dirty_files(&dirty_paths, show_progress);
+    let (parse_results, parse_failures, phase2_elapsed) =
+        parse::parse_dirty_files(&dirty_paths, show_progress);
+    print_parse_failures(&parse_failures, &root);
+    if !quiet {
+        packages::warn_conflicting_go_packages(&parse_results, &root);
+    }
+    if options.fail_on_parse_error && !parse_failures.is_empty() {
+        anyhow::bail!(
+            "{} file(s) failed to parse; nothing was written (--fail-on-parse-error)",
+            parse_failures.len()
+        );
+    }
 
     // Phase 2b (parallel): pre-serialize JSON fields before the single-threaded
     // SQLite write phase.
@@ -130,12 +227,14 @@ pub fn generate_with_git(
     store.write_meta(git_meta.as_ref())?;
     let total_elapsed = total_start.elapsed();
 
-    println!(
-        "{} {} file(s) indexed in {:.1}s",
-        "Done ✓".green().bold(),
-        serialized_rows.len(),
-        total_elapsed.as_secs_f64()
-    );
+    if !json {
+        println!(
+            "{} {} file(s) indexed in {:.1}s",
+            "Done ✓".green().bold(),
+            serialized_rows.len(),
+            total_elapsed.as_secs_f64()
+        );
+    }
 
     if !quiet {
         print_phase_timings(
@@ -147,7 +246,28 @@ pub fn generate_with_git(
             phase4_elapsed,
         );
     }
+    if options.stats {
+        print_index_stats(&store, &files, &root)?;
+    }
 
+    finish(
+        GenerateSummary::new(serialized_rows.len(), &parse_failures, &root),
+        json,
+    )
+}
+
+fn finish(summary: GenerateSummary, json: bool) -> Result<GenerateSummary> {
+    if json {
+        println!("{}", serde_json::to_string_pretty(&summary)?);
+    }
+    Ok(summary)
+}
+
+/// `--stats` over `files`, read back from the index once this run has
+/// updated it.
+fn print_index_stats(store: &SqliteStore, files: &[PathBuf], root: &Path) -> Result<()> {
+    let manifest = store.load_manifest()?;
+    stats::print_stats(&manifest, files, root);
     Ok(())
 }
 
@@ -167,9 +287,11 @@ fn finish_removed_only_update(
     git_meta: Option<&GitMeta>,
     total_start: Instant,
     phase1_elapsed: Duration,
-    quiet: bool,
+    options: &GenerateOptions,
     removed_count: usize,
 ) -> Result<()> {
+    let json = options.json;
+    let quiet = options.quiet || json;
     if !quiet {
         println!("Found {removed_count} removed file(s)");
     }
@@ -184,12 +306,14 @@ fn finish_removed_only_update(
 
     store.write_meta(git_meta)?;
     let total_elapsed = total_start.elapsed();
-    println!(
-        "{} {} file(s) pruned in {:.1}s",
-        "Done ✓".green().bold(),
-        removed_count,
-        total_elapsed.as_secs_f64()
-    );
+    if !json {
+        println!(
+            "{} {} file(s) pruned in {:.1}s",
+            "Done ✓".green().bold(),
+            removed_count,
+            total_elapsed.as_secs_f64()
+        );
+    }
 
     if !quiet {
         print_phase_timings(
@@ -251,7 +375,7 @@ fn serialize_dirty_rows(
 
 pub fn validate(paths: &[String]) -> Result<()> {
     let config = Config::load().unwrap_or_default();
-    let (files, _) = collect_files_multi(paths, &config)?;
+    let (files, _) = collect_files_multi(paths, &config, true)?;
     let root = resolve_root_multi(paths)?;
 
     if files.is_empty() {
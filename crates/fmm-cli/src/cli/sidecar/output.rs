@@ -5,6 +5,7 @@ use indicatif::{ProgressBar, ProgressStyle};
 use std::path::{Path, PathBuf};
 use std::time::Duration;
 
+use super::parse::ParseFailure;
 use super::staleness;
 
 pub(super) fn start_spinner(message: &str) -> ProgressBar {
@@ -87,6 +88,24 @@ pub(super) fn print_files_summary(total: usize, skipped: usize, dirty: usize) {
     }
 }
 
+/// One `warning:` line per `.fmmrc.toml` key that was ignored as unknown.
+pub(super) fn print_unknown_config_keys(config: &Config) {
+    for key in &config.unknown_keys {
+        eprintln!(
+            "{} .fmmrc.toml: unknown key `{key}` ignored",
+            "warning:".yellow()
+        );
+    }
+}
+
+/// One `error:` line per file that failed to parse, paths relative to `root`.
+pub(super) fn print_parse_failures(failures: &[ParseFailure], root: &Path) {
+    for (path, error) in failures {
+        let rel = path.strip_prefix(root).unwrap_or(path);
+        eprintln!("{} {}: {}", "error:".red().bold(), rel.display(), error);
+    }
+}
+
 pub(super) fn print_phase_timings(
     total: Duration,
     phase1: Duration,
@@ -0,0 +1,241 @@
+use colored::Colorize;
+use std::collections::{BTreeMap, HashSet};
+use std::path::{Path, PathBuf};
+
+use fmm_core::parser::ParseResult;
+
+/// Package name → files declaring it, for one directory.
+type PackagesInDir = BTreeMap<String, Vec<PathBuf>>;
+
+/// Warn about directories whose Go files declare more than one package. The
+/// files are indexed either way; each keeps its own `package` field.
+pub(super) fn warn_conflicting_go_packages(parse_results: &[(PathBuf, ParseResult)], root: &Path) {
+    for (dir, packages) in conflicting_go_packages(parse_results) {
+        let rel = dir.strip_prefix(root).unwrap_or(&dir);
+        let dir_label = if rel.as_os_str().is_empty() {
+            ".".to_string()
+        } else {
+            rel.display().to_string()
+        };
+        let listing: Vec<String> = packages
+            .iter()
+            .map(|(package, files)| {
+                let names: Vec<String> = files
+                    .iter()
+                    .filter_map(|file| file.file_name())
+                    .map(|name| name.to_string_lossy().into_owned())
+                    .collect();
+                format!("{package} ({})", names.join(", "))
+            })
+            .collect();
+        eprintln!(
+            "{} {}: Go files declare more than one package: {}",
+            "warning:".yellow(),
+            dir_label,
+            listing.join("; ")
+        );
+    }
+}
+
+/// Directories holding a Go file parsed in this run whose Go files disagree on
+/// their package. The other files in those directories are read from disk, so
+/// an edit to one file is checked against the rest of its package.
+fn conflicting_go_packages(
+    parse_results: &[(PathBuf, ParseResult)],
+) -> Vec<(PathBuf, PackagesInDir)> {
+    let mut by_dir: BTreeMap<PathBuf, PackagesInDir> = BTreeMap::new();
+    for (path, result) in parse_results {
+        let Some((dir, package)) = go_package(path, result) else {
+            continue;
+        };
+        by_dir
+            .entry(dir.to_path_buf())
+            .or_default()
+            .entry(package.to_string())
+            .or_default()
+            .push(path.clone());
+    }
+    for (dir, packages) in &mut by_dir {
+        let parsed: HashSet<PathBuf> = packages.values().flatten().cloned().collect();
+        for path in go_files_in(dir) {
+            if parsed.contains(&path) {
+                continue;
+            }
+            if let Some(package) = read_go_package(&path) {
+                packages.entry(package).or_default().push(path);
+            }
+        }
+        for files in packages.values_mut() {
+            files.sort();
+        }
+    }
+    by_dir
+        .into_iter()
+        .filter(|(_, packages)| packages.len() > 1)
+        .collect()
+}
+
+/// The `.go` files directly inside `dir`; none when it cannot be read.
+fn go_files_in(dir: &Path) -> Vec<PathBuf> {
+    let Ok(entries) = std::fs::read_dir(dir) else {
+        return Vec::new();
+    };
+    entries
+        .filter_map(|entry| entry.ok().map(|entry| entry.path()))
+        .filter(|path| path.is_file() && path.extension().is_some_and(|ext| ext == "go"))
+        .collect()
+}
+
+/// The directory and package a parsed Go file belongs to. An external test
+/// package `foo_test` in a `_test.go` file counts as `foo`, and files under
+/// `//go:build ignore` belong to no package, as the go tool treats both.
+pub(super) fn go_package<'a>(
+    path: &'a Path,
+    result: &'a ParseResult,
+) -> Option<(&'a Path, &'a str)> {
+    let fields = result.custom_fields.as_ref()?;
+    let package = fields.get("package")?.as_str()?;
+    if fields
+        .get("build_constraint")
+        .and_then(|value| value.as_str())
+        .is_some_and(|constraint| constraint == "ignore")
+    {
+        return None;
+    }
+    Some((path.parent()?, tested_package(path, package)))
+}
+
+/// The package `path` belongs to, read from its package clause without a
+/// full parse, with the same rules as [`go_package`]. None when the file
+/// cannot be read, has no package clause, or is under `//go:build ignore`.
+pub(crate) fn read_go_package(path: &Path) -> Option<String> {
+    if path.extension().is_none_or(|ext| ext != "go") {
+        return None;
+    }
+    let source = std::fs::read_to_string(path).ok()?;
+    let (package, ignored) = package_clause(&source)?;
+    (!ignored).then(|| tested_package(path, package).to_string())
+}
+
+/// `foo` for an external test package `foo_test` declared in a `_test.go`
+/// file, otherwise `package` itself.
+fn tested_package<'a>(path: &Path, package: &'a str) -> &'a str {
+    let is_test_file = path
+        .file_name()
+        .and_then(|name| name.to_str())
+        .is_some_and(|name| name.ends_with("_test.go"));
+    match package.strip_suffix("_test") {
+        Some(tested) if is_test_file => tested,
+        _ => package,
+    }
+}
+
+/// The name in a Go file's package clause, skipping the comments before it,
+/// and whether one of those is a `//go:build ignore` constraint.
+fn package_clause(source: &str) -> Option<(&str, bool)> {
+    let mut ignored = false;
+    let mut rest = source;
+    loop {
+        rest = rest.trim_start();
+        if let Some(comment) = rest.strip_prefix("//") {
+            let (line, tail) = comment.split_once('\n').unwrap_or((comment, ""));
+            ignored |= line.strip_prefix("go:build").map(str::trim) == Some("ignore");
+            rest = tail;
+        } else if let Some(comment) = rest.strip_prefix("/*") {
+            rest = &comment[comment.find("*/")? + 2..];
+        } else {
+            break;
+        }
+    }
+    let name = rest
+        .strip_prefix("package")?
+        .strip_prefix(char::is_whitespace)?
+        .trim_start();
+    let end = name
+        .find(|c: char| !(c.is_alphanumeric() || c == '_'))
+        .unwrap_or(name.len());
+    (end > 0).then(|| (&name[..end], ignored))
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use std::collections::{BTreeSet, HashMap};
+
+    fn go_file(path: &str, package: &str) -> (PathBuf, ParseResult) {
+        let fields = HashMap::from([("package".to_string(), serde_json::json!(package))]);
+        let result = ParseResult {
+            metadata: Default::default(),
+            custom_fields: Some(fields),
+        };
+        (PathBuf::from(path), result)
+    }
+
+    #[test]
+    fn package_clause_skips_leading_comments() {
+        let source = "// Copyright notice.\n\n/* Package doc\n   spans lines. */\npackage server // trailing\n";
+        assert_eq!(package_clause(source), Some(("server", false)));
+        assert_eq!(
+            package_clause("//go:build ignore\n\npackage main\n"),
+            Some(("main", true))
+        );
+        assert_eq!(package_clause("// no clause\n"), None);
+        assert_eq!(package_clause("packages foo\n"), None);
+    }
+
+    #[test]
+    fn external_test_packages_do_not_conflict() {
+        let results = [
+            go_file("/repo/server/server.go", "server"),
+            go_file("/repo/server/server_test.go", "server_test"),
+            go_file("/repo/cmd/main.go", "main"),
+        ];
+        assert!(conflicting_go_packages(&results).is_empty());
+    }
+
+    #[test]
+    fn different_packages_in_one_directory_conflict() {
+        let results = [
+            go_file("/repo/server/server.go", "server"),
+            go_file("/repo/server/tool.go", "main"),
+            go_file("/repo/server/tool_test.go", "main"),
+        ];
+        let conflicts = conflicting_go_packages(&results);
+        assert_eq!(conflicts.len(), 1);
+        let (dir, packages) = &conflicts[0];
+        assert_eq!(dir, Path::new("/repo/server"));
+        assert_eq!(
+            packages.keys().collect::<BTreeSet<_>>(),
+            BTreeSet::from([&"main".to_string(), &"server".to_string()])
+        );
+    }
+
+    #[test]
+    fn parsed_files_are_checked_against_unchanged_siblings() {
+        let tmp = tempfile::TempDir::new().unwrap();
+        std::fs::write(tmp.path().join("server.go"), "package server\n").unwrap();
+        std::fs::write(tmp.path().join("server_test.go"), "package server_test\n").unwrap();
+        let tool = tmp.path().join("tool.go");
+        std::fs::write(&tool, "package main\n").unwrap();
+
+        let results = [go_file(tool.to_str().unwrap(), "main")];
+        let conflicts = conflicting_go_packages(&results);
+        assert_eq!(conflicts.len(), 1);
+        let packages = &conflicts[0].1;
+        assert_eq!(packages["main"], vec![tool]);
+        assert_eq!(
+            packages["server"],
+            vec![
+                tmp.path().join("server.go"),
+                tmp.path().join("server_test.go")
+            ]
+        );
+
+        let results = [go_file(
+            tmp.path().join("server.go").to_str().unwrap(),
+            "server",
+        )];
+        std::fs::remove_file(tmp.path().join("tool.go")).unwrap();
+        assert!(conflicting_go_packages(&results).is_empty());
+    }
+}
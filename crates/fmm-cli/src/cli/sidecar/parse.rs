@@ -1,4 +1,3 @@
-use colored::Colorize;
 use indicatif::{ProgressBar, ProgressStyle};
 use rayon::prelude::*;
 use std::path::PathBuf;
@@ -11,20 +10,37 @@ use std::time::{Duration, Instant, SystemTime, UNIX_EPOCH};
 use fmm_core::extractor::ParserCache;
 use fmm_core::parser::ParseResult;
 
+/// A file the parser rejected, with the error message.
+pub(crate) type ParseFailure = (PathBuf, String);
+
+type ParseOutcome = Result<(PathBuf, ParseResult), ParseFailure>;
+
+/// Parse `dirty_files` across the rayon pool. Results come back in input
+/// order regardless of which worker finishes first, so the write phase sees
+/// the same sequence as a single-threaded run. Files that fail to parse are
+/// returned separately, also in input order, and never stop the others.
 pub(crate) fn parse_dirty_files(
     dirty_files: &[&PathBuf],
     show_progress: bool,
-) -> (Vec<(PathBuf, ParseResult)>, Duration) {
+) -> (Vec<(PathBuf, ParseResult)>, Vec<ParseFailure>, Duration) {
     let start = Instant::now();
-    let parse_results = if show_progress {
+    let outcomes = if show_progress {
         parse_with_progress(dirty_files)
     } else {
         parse_without_progress(dirty_files)
     };
-    (parse_results, start.elapsed())
+    let mut parse_results = Vec::with_capacity(outcomes.len());
+    let mut failures = Vec::new();
+    for outcome in outcomes {
+        match outcome {
+            Ok(parsed) => parse_results.push(parsed),
+            Err(failure) => failures.push(failure),
+        }
+    }
+    (parse_results, failures, start.elapsed())
 }
 
-fn parse_with_progress(dirty_files: &[&PathBuf]) -> Vec<(PathBuf, ParseResult)> {
+fn parse_with_progress(dirty_files: &[&PathBuf]) -> Vec<ParseOutcome> {
     let pb = ProgressBar::new(dirty_files.len() as u64);
     pb.set_style(
         ProgressStyle::with_template(
@@ -67,38 +83,31 @@ fn parse_with_progress(dirty_files: &[&PathBuf]) -> Vec<(PathBuf, ParseResult)>
     });
 
     let results = dirty_files
-        .iter()
-        .par_bridge()
+        .par_iter()
         .map_init(ParserCache::new, |cache, file| {
             let r = parse_one(cache, file);
             last_inc_ms.store(now_ms(), Ordering::Relaxed);
             pb.inc(1);
             r
         })
-        .filter_map(|x| x)
         .collect();
     pb.finish_and_clear();
     let _ = watcher.join();
     results
 }
 
-fn parse_without_progress(dirty_files: &[&PathBuf]) -> Vec<(PathBuf, ParseResult)> {
+fn parse_without_progress(dirty_files: &[&PathBuf]) -> Vec<ParseOutcome> {
     dirty_files
-        .iter()
-        .par_bridge()
+        .par_iter()
         .map_init(ParserCache::new, parse_one)
-        .filter_map(|x| x)
         .collect()
 }
 
-fn parse_one(cache: &mut ParserCache, file: &&PathBuf) -> Option<(PathBuf, ParseResult)> {
-    match cache.parse_file(file) {
-        Ok(result) => Some(((*file).clone(), result)),
-        Err(e) => {
-            eprintln!("{} {}: {}", "error:".red().bold(), file.display(), e);
-            None
-        }
-    }
+fn parse_one(cache: &mut ParserCache, file: &&PathBuf) -> ParseOutcome {
+    cache
+        .parse_file(file)
+        .map(|result| ((*file).clone(), result))
+        .map_err(|e| ((*file).clone(), e.to_string()))
 }
 
 fn now_ms() -> u64 {
@@ -0,0 +1,176 @@
+use std::collections::BTreeMap;
+use std::path::{Path, PathBuf};
+
+use fmm_core::manifest::private_members::extract_top_level_functions;
+use fmm_core::manifest::{FileEntry, Manifest, SymbolMetadata};
+
+use super::packages::read_go_package;
+
+/// Totals over a set of indexed files.
+#[derive(Default)]
+struct Stats {
+    files: usize,
+    loc: usize,
+    exported: usize,
+    unexported: usize,
+    /// Declaration kind → symbol count. Symbols with no recorded kind are `other`.
+    kinds: BTreeMap<String, usize>,
+}
+
+impl Stats {
+    /// Count `entry` and the non-exported declarations of `rel_file`, which
+    /// the index does not keep and are read from the file as
+    /// `fmm outline --include-private` does.
+    fn add(&mut self, root: &Path, rel_file: &str, entry: &FileEntry) {
+        self.files += 1;
+        self.loc += entry.loc;
+        let exports: Vec<&str> = entry.exports.iter().map(String::as_str).collect();
+        let indexed = exports
+            .iter()
+            .filter_map(|name| entry.export_metadata.get(*name))
+            .chain(entry.method_metadata.values());
+        for metadata in indexed {
+            let hidden = matches!(
+                metadata.visibility.as_deref(),
+                Some("private" | "non_exported")
+            );
+            self.add_symbol(metadata, hidden, "other");
+        }
+        // As in the outline, a declaration without a recorded kind is a function.
+        for declaration in extract_top_level_functions(root, rel_file, &exports) {
+            self.add_symbol(&declaration.metadata, true, "fn");
+        }
+    }
+
+    fn add_symbol(&mut self, metadata: &SymbolMetadata, hidden: bool, default_kind: &str) {
+        if hidden {
+            self.unexported += 1;
+        } else {
+            self.exported += 1;
+        }
+        let kind = metadata.declaration_kind.as_deref().unwrap_or(default_kind);
+        *self.kinds.entry(kind.to_string()).or_default() += 1;
+    }
+
+    fn summary(&self) -> String {
+        format!(
+            "{} files · {} lines · {} symbols ({} exported, {} unexported)",
+            self.files,
+            self.loc,
+            self.exported + self.unexported,
+            self.exported,
+            self.unexported
+        )
+    }
+
+    fn kinds_line(&self) -> String {
+        let kinds: Vec<String> = self
+            .kinds
+            .iter()
+            .map(|(kind, count)| format!("{kind} {count}"))
+            .collect();
+        kinds.join(" · ")
+    }
+}
+
+/// Print `--stats` for `files`, the files this run was asked to index, to
+/// stderr: totals, symbols by kind, and, when they hold more than one Go
+/// package, a line per package. Counts come from the index after the run, so
+/// files that were already up to date are counted too and the numbers match
+/// what `fmm outline` reports for the same files.
+pub(super) fn print_stats(manifest: &Manifest, files: &[PathBuf], root: &Path) {
+    let mut total = Stats::default();
+    let mut packages: BTreeMap<(PathBuf, String), Stats> = BTreeMap::new();
+    for path in files {
+        let rel = path
+            .strip_prefix(root)
+            .unwrap_or(path)
+            .display()
+            .to_string();
+        let Some(entry) = manifest.files.get(&rel) else {
+            continue;
+        };
+        total.add(root, &rel, entry);
+        if let (Some(dir), Some(package)) = (path.parent(), read_go_package(path)) {
+            packages
+                .entry((dir.to_path_buf(), package))
+                .or_default()
+                .add(root, &rel, entry);
+        }
+    }
+
+    eprintln!("Stats: {}", total.summary());
+    if !total.kinds.is_empty() {
+        eprintln!("  {}", total.kinds_line());
+    }
+    if packages.len() > 1 {
+        for ((dir, package), stats) in &packages {
+            let rel = dir.strip_prefix(root).unwrap_or(dir);
+            let dir_label = if rel.as_os_str().is_empty() {
+                ".".to_string()
+            } else {
+                rel.display().to_string()
+            };
+            eprintln!("  {package} ({dir_label}): {}", stats.summary());
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use fmm_core::parser::{DeclarationKind, ExportEntry, Metadata, SymbolVisibility};
+
+    fn entry(name: &str, kind: DeclarationKind, visibility: SymbolVisibility) -> ExportEntry {
+        let mut entry = ExportEntry::new(name.to_string(), 1, 1);
+        entry.declaration_kind = Some(kind);
+        entry.visibility = Some(visibility);
+        entry
+    }
+
+    #[test]
+    fn counts_symbols_by_kind_and_visibility() {
+        let file = FileEntry::from(Metadata {
+            exports: vec![
+                entry("Handler", DeclarationKind::Struct, SymbolVisibility::Public),
+                entry("Process", DeclarationKind::Fn, SymbolVisibility::Public),
+                entry("helper", DeclarationKind::Fn, SymbolVisibility::NonExported),
+            ],
+            loc: 40,
+            ..Default::default()
+        });
+        let mut stats = Stats::default();
+        stats.add(Path::new("/nonexistent"), "server.go", &file);
+        assert_eq!(
+            stats.summary(),
+            "1 files · 40 lines · 3 symbols (2 exported, 1 unexported)"
+        );
+        assert_eq!(stats.kinds_line(), "fn 2 · struct 1");
+    }
+
+    #[test]
+    fn counts_unexported_go_declarations_from_the_file() {
+        let tmp = tempfile::TempDir::new().unwrap();
+        std::fs::write(
+            tmp.path().join("server.go"),
+            "package server\n\nfunc Serve() {}\n\nfunc helper() {}\n\ntype state struct{}\n",
+        )
+        .unwrap();
+        let file = FileEntry::from(Metadata {
+            exports: vec![entry(
+                "Serve",
+                DeclarationKind::Fn,
+                SymbolVisibility::Public,
+            )],
+            loc: 7,
+            ..Default::default()
+        });
+        let mut stats = Stats::default();
+        stats.add(tmp.path(), "server.go", &file);
+        assert_eq!(
+            stats.summary(),
+            "1 files · 7 lines · 3 symbols (1 exported, 2 unexported)"
+        );
+        assert_eq!(stats.kinds_line(), "fn 2 · struct 1");
+    }
+}
@@ -20,8 +20,11 @@ pub fn status() -> Result<()> {
     println!("{}", "=".repeat(40).dimmed());
 
     println!("\n{}", "Configuration:".yellow().bold());
-    if std::path::Path::new(".fmmrc.toml").exists() {
-        println!("  {} .fmmrc.toml found", "✓".green());
+    if let Some(path) = Config::find_file(std::path::Path::new(".")) {
+        println!("  {} {} found", "✓".green(), path.display());
+        for key in &config.unknown_keys {
+            println!("  {} Unknown key `{key}` ignored", "!".yellow());
+        }
     } else {
         println!("  {} No config file (using defaults)", "!".yellow());
     }
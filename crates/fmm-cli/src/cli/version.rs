@@ -0,0 +1,92 @@
+use anyhow::Result;
+use colored::Colorize;
+
+use fmm_core::parser::ParserRegistry;
+
+use super::commands::{EXPORTS_FEATURES, GENERATE_FEATURES, OUTLINE_FEATURES};
+
+/// Output modes of `fmm outline`.
+const OUTLINE_FORMATS: &[&str] = &["yaml", "json", "signature-only", "tree"];
+
+/// Indexing capabilities with no flag of their own.
+const INDEX_FEATURES: &[&str] = &["go-generics", "go-custom-fields"];
+
+/// Capabilities a script can check for before relying on them. Each command
+/// lists the names for its flags next to their definitions; an absent name
+/// means an older fmm.
+fn features() -> Vec<&'static str> {
+    [
+        INDEX_FEATURES,
+        OUTLINE_FEATURES,
+        GENERATE_FEATURES,
+        EXPORTS_FEATURES,
+    ]
+    .concat()
+}
+
+#[derive(serde::Serialize)]
+struct VersionJson {
+    version: &'static str,
+    languages: Vec<LanguageJson>,
+    outline_formats: &'static [&'static str],
+    features: Vec<&'static str>,
+}
+
+#[derive(serde::Serialize)]
+struct LanguageJson {
+    id: &'static str,
+    extensions: &'static [&'static str],
+}
+
+pub fn version(json_output: bool) -> Result<()> {
+    let registry = ParserRegistry::with_builtins();
+    let mut languages: Vec<LanguageJson> = registry
+        .descriptors()
+        .iter()
+        .map(|language| LanguageJson {
+            id: language.language_id,
+            extensions: language.extensions,
+        })
+        .collect();
+    languages.sort_by_key(|language| language.id);
+    let info = VersionJson {
+        version: crate::VERSION,
+        languages,
+        outline_formats: OUTLINE_FORMATS,
+        features: features(),
+    };
+
+    if json_output {
+        println!("{}", serde_json::to_string_pretty(&info)?);
+        return Ok(());
+    }
+
+    println!("{} {}", "fmm".bold(), info.version);
+    println!("\n{}", "Languages:".yellow().bold());
+    for language in &info.languages {
+        let extensions: Vec<String> = language
+            .extensions
+            .iter()
+            .map(|extension| format!(".{extension}"))
+            .collect();
+        println!("  {} {}", language.id, extensions.join(", ").dimmed());
+    }
+    println!("\n{}", "Outline formats:".yellow().bold());
+    println!("  {}", info.outline_formats.join(", "));
+    println!("\n{}", "Features:".yellow().bold());
+    println!("  {}", info.features.join(", "));
+    Ok(())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn feature_names_are_unique() {
+        let mut seen = std::collections::HashSet::new();
+        for feature in features() {
+            assert!(seen.insert(feature), "{feature} is listed twice");
+        }
+    }
+}
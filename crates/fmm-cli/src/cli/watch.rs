@@ -14,7 +14,7 @@ use fmm_store::SqliteStore;
 
 use fmm_core::config::Config;
 
-use super::{collect_files, resolve_root};
+use super::{collect_files, is_skipped_go_file, resolve_root};
 
 pub fn watch(path: &str, debounce_ms: u64) -> Result<()> {
     let config = Config::load().unwrap_or_default();
@@ -107,7 +107,7 @@ fn handle_event(
     root: &std::path::PathBuf,
     updates: &AtomicUsize,
 ) {
-    if !is_watchable(path, config) {
+    if !is_watchable(path, config) || is_skipped_go_file(path, root) {
         return;
     }
 
@@ -289,6 +289,27 @@ mod tests {
         assert_eq!(updates.load(Ordering::Relaxed), 1);
     }
 
+    #[test]
+    fn handle_create_skips_go_vendor_files() {
+        let tmp = TempDir::new().unwrap();
+        let vendored = tmp.path().join("vendor/github.com/lib/lib.go");
+        fs::create_dir_all(vendored.parent().unwrap()).unwrap();
+        fs::write(&vendored, "package lib\n\nfunc Exported() {}\n").unwrap();
+        let root = tmp.path().canonicalize().unwrap();
+        let updates = AtomicUsize::new(0);
+
+        handle_event(
+            &root.join("vendor/github.com/lib/lib.go"),
+            &notify::EventKind::Create(CreateKind::File),
+            &Config::default(),
+            &root,
+            &updates,
+        );
+
+        assert_eq!(updates.load(Ordering::Relaxed), 0);
+        assert!(!root.join(fmm_store::DB_FILENAME).exists());
+    }
+
     #[test]
     fn handle_modify_reindexes_file() {
         let (tmp, config) = setup_watch_project();
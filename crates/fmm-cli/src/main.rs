@@ -1,7 +1,8 @@
-use clap::{CommandFactory, Parser as ClapParser};
+use clap::{CommandFactory, FromArgMatches};
 use colored::Colorize;
 use fmm::cli::{self, Cli, Commands};
 use fmm::mcp;
+use fmm_core::config::Config;
 
 fn main() {
     if let Err(err) = run() {
@@ -34,7 +35,15 @@ fn print_error(err: &anyhow::Error) {
 }
 
 fn run() -> anyhow::Result<()> {
-    let cli_args = Cli::parse();
+    let config = Config::load().unwrap_or_default();
+    let (command, ignored) = cli::with_flag_defaults(Cli::command(), &config);
+    for key in &ignored {
+        eprintln!(
+            "{} .fmmrc.toml: `{key}` is not a command or a flag that takes a value; ignored",
+            "warning:".yellow()
+        );
+    }
+    let cli_args = Cli::from_arg_matches(&command.get_matches()).unwrap_or_else(|err| err.exit());
 
     if cli_args.markdown_help {
         let markdown = clap_markdown::help_markdown::<Cli>();
@@ -72,14 +81,33 @@ fn run() -> anyhow::Result<()> {
 fn run_command(command: Commands) -> anyhow::Result<()> {
     match command {
         Commands::Generate(args) => {
-            cli::generate_with_git(
+            if let Some(jobs) = args.jobs {
+                rayon::ThreadPoolBuilder::new()
+                    .num_threads(jobs.get())
+                    .build_global()?;
+            }
+            let summary = cli::generate_with_options(
                 &args.paths,
-                args.dry_run,
-                args.force,
-                args.quiet,
-                args.sha.as_deref(),
-                args.no_git,
+                &cli::GenerateOptions {
+                    dry_run: args.dry_run,
+                    force: args.force,
+                    quiet: args.quiet,
+                    sha_override: args.sha,
+                    no_git: args.no_git,
+                    no_recurse: args.no_recurse,
+                    fail_on_parse_error: args.fail_on_parse_error,
+                    stats: args.stats,
+                    include: args.include,
+                    exclude: args.exclude,
+                    json: args.json,
+                },
             )?;
+            if !summary.errors.is_empty() {
+                anyhow::bail!(
+                    "{} file(s) failed to parse; the other files were indexed",
+                    summary.errors.len()
+                );
+            }
         }
         Commands::Validate(args) => {
             println!("{}", "Validating index...".green().bold());
@@ -98,6 +126,9 @@ fn run_command(command: Commands) -> anyhow::Result<()> {
         Commands::Status => {
             cli::status()?;
         }
+        Commands::Version(args) => {
+            cli::version(args.json)?;
+        }
         Commands::Search(args) => {
             cli::search(cli::SearchOptions {
                 term: args.term,
@@ -172,7 +203,37 @@ fn run_command(command: Commands) -> anyhow::Result<()> {
             )?;
         }
         Commands::Outline(args) => {
-            cli::outline(&args.file, args.include_private, args.json)?;
+            if args.no_color {
+                colored::control::set_override(false);
+            }
+            cli::outline(
+                &args.file,
+                cli::OutlineOptions {
+                    include_private: args.include_private,
+                    lang: args.lang,
+                    json_output: args.json,
+                    signature_only: args.signature_only,
+                    tree: args.tree,
+                    qualify_imports: args.qualify_imports,
+                    expand_embeds: args.expand_embeds,
+                    sort: args.sort,
+                    context: args.context,
+                    annotate_cmd: args.annotate_cmd,
+                    min_complexity: args.min_complexity,
+                    markers: args.markers,
+                    marker_tags: args.marker_tags,
+                    eval_consts: args.eval_consts,
+                    only_deprecated: args.only_deprecated,
+                    callgraph: args.callgraph,
+                    undocumented: args.undocumented,
+                    max_undocumented: args.max_undocumented,
+                    receiver_report: args.receiver_report,
+                    imports_only: args.imports_only,
+                    layout: args.layout,
+                    error_report: args.error_report,
+                    shadow_report: args.shadow_report,
+                },
+            )?;
         }
         Commands::Ls(args) => {
             cli::ls(
@@ -188,15 +249,18 @@ fn run_command(command: Commands) -> anyhow::Result<()> {
             )?;
         }
         Commands::Exports(args) => {
-            cli::exports(
-                args.pattern.as_deref(),
-                args.file.as_deref(),
-                args.dir.as_deref(),
-                &args.filter,
-                args.limit,
-                args.offset,
-                args.json,
-            )?;
+            cli::exports(cli::ExportsOptions {
+                pattern: args.pattern,
+                file: args.file,
+                directory: args.dir,
+                filter: args.filter,
+                kinds: args.kinds,
+                name_exclude: args.name_exclude,
+                limit: args.limit,
+                offset: args.offset,
+                json_output: args.json,
+                api_hash: args.api_hash,
+            })?;
         }
         Commands::Mcp | Commands::Serve => {
             let mut server = mcp::McpServer::new();
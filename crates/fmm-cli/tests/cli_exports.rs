@@ -270,3 +270,203 @@ fn exports_help_documents_file_flag() {
         "got: {stdout}"
     );
 }
+
+#[test]
+fn exports_kind_keeps_only_selected_kinds() {
+    let tmp = setup_export_project();
+    let output = run_fmm(
+        tmp.path(),
+        &[
+            "exports",
+            "--file",
+            "src/app.ts",
+            "--kind",
+            "const",
+            "--json",
+        ],
+    );
+
+    assert!(
+        output.status.success(),
+        "fmm exports failed: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: Value = serde_json::from_slice(&output.stdout).unwrap();
+    let exports = json["exports"].as_array().unwrap();
+
+    assert_eq!(exports.len(), 1, "got: {json}");
+    assert_eq!(exports[0]["name"], "APP_VERSION");
+    assert_eq!(exports[0]["lines"], serde_json::json!([5, 5]));
+}
+
+#[test]
+fn exports_repeated_kind_values_union() {
+    let tmp = setup_export_project();
+    let output = run_fmm(
+        tmp.path(),
+        &[
+            "exports",
+            "--file",
+            "src/app.ts",
+            "--kind",
+            "fn",
+            "--kind",
+            "const",
+        ],
+    );
+
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(stdout.contains("createApp"), "got: {stdout}");
+    assert!(stdout.contains("APP_VERSION"), "got: {stdout}");
+}
+
+#[test]
+fn exports_kind_tells_go_vars_from_consts() {
+    let tmp = TempDir::new().unwrap();
+    write_file(
+        tmp.path(),
+        "config.go",
+        "package config\n\nconst MaxRetries = 3\n\nvar Default = Config{}\n\ntype Config struct{}\n",
+    );
+    fmm::cli::generate(
+        &[tmp.path().to_str().unwrap().to_string()],
+        false,
+        false,
+        true,
+    )
+    .unwrap();
+
+    for (kind, name) in [("const", "MaxRetries"), ("var", "Default")] {
+        let output = run_fmm(
+            tmp.path(),
+            &["exports", "--file", "config.go", "--kind", kind, "--json"],
+        );
+        assert!(output.status.success());
+        let json: Value = serde_json::from_slice(&output.stdout).unwrap();
+        let names: Vec<&str> = json["exports"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .filter_map(|export| export["name"].as_str())
+            .collect();
+        assert_eq!(names, vec![name], "--kind {kind}");
+    }
+}
+
+#[test]
+fn exports_kind_composes_with_file_type_filter() {
+    let tmp = setup_export_project();
+    let output = run_fmm(
+        tmp.path(),
+        &["exports", "Helper", "--kind", "fn", "--filter", "source"],
+    );
+
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(stdout.contains("loadHelper"), "got: {stdout}");
+    assert!(!stdout.contains("testHelper"), "got: {stdout}");
+}
+
+#[test]
+fn exports_name_exclude_drops_matching_names_after_the_pattern() {
+    let tmp = setup_export_project();
+    let output = run_fmm(
+        tmp.path(),
+        &["exports", "^create", "--name-exclude", "Other$", "--json"],
+    );
+
+    assert!(output.status.success());
+    let json: Value = serde_json::from_slice(&output.stdout).unwrap();
+    let names: Vec<&str> = json
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|export| export["name"].as_str().unwrap())
+        .collect();
+    assert_eq!(names, ["createApp"]);
+}
+
+#[test]
+fn exports_invalid_name_exclude_fails_before_reading_the_index() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm(tmp.path(), &["exports", "--name-exclude", "("]);
+
+    assert!(!output.status.success());
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("Invalid --name-exclude"), "got: {stderr}");
+}
+
+#[test]
+fn exports_unknown_kind_lists_valid_values() {
+    let tmp = setup_export_project();
+    let output = run_fmm(tmp.path(), &["exports", "--kind", "func"]);
+
+    assert!(!output.status.success());
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("possible values"), "got: {stderr}");
+    assert!(stderr.contains("method"), "got: {stderr}");
+}
+
+#[test]
+fn exports_api_hash_changes_with_the_api_not_the_formatting() {
+    let tmp = TempDir::new().unwrap();
+    let api_hash = |files: &[(&str, &str)]| {
+        for (rel, content) in files {
+            write_file(tmp.path(), rel, content);
+        }
+        fmm::cli::generate(
+            &[tmp.path().to_str().unwrap().to_string()],
+            false,
+            false,
+            true,
+        )
+        .unwrap();
+        let output = run_fmm(tmp.path(), &["exports", "--api-hash", "--json"]);
+        assert!(
+            output.status.success(),
+            "fmm exports --api-hash failed: {}",
+            String::from_utf8_lossy(&output.stderr)
+        );
+        let json: Value = serde_json::from_slice(&output.stdout).unwrap();
+        assert_eq!(json.as_array().unwrap().len(), 1, "got: {json}");
+        assert_eq!(json[0]["package"], "server");
+        assert_eq!(json[0]["dir"], "server");
+        json[0]["hash"].as_str().unwrap().to_string()
+    };
+
+    let baseline = api_hash(&[
+        (
+            "server/server.go",
+            "package server\n\nfunc Serve(addr string) error { return nil }\n",
+        ),
+        (
+            "server/client.go",
+            "package server\n\ntype Client struct{ Addr string }\n\nfunc (c *Client) Dial() error { return nil }\n",
+        ),
+        (
+            "server/server_test.go",
+            "package server\n\nfunc TestServe(t *testing.T) {}\n",
+        ),
+    ]);
+    assert_eq!(baseline.len(), 64, "got: {baseline}");
+
+    let reformatted = api_hash(&[(
+        "server/server.go",
+        "package server\n\n// Serve listens on addr.\nfunc Serve(\n\taddr string,\n) error {\n\treturn nil\n}\n\nfunc helper() {}\n",
+    )]);
+    assert_eq!(reformatted, baseline);
+
+    let renamed = api_hash(&[(
+        "server/server.go",
+        "package server\n\nfunc Listen(addr string) error { return nil }\n",
+    )]);
+    assert_ne!(renamed, baseline);
+
+    let retyped = api_hash(&[(
+        "server/server.go",
+        "package server\n\nfunc Serve(addr string, port int) error { return nil }\n",
+    )]);
+    assert_ne!(retyped, baseline);
+    assert_ne!(retyped, renamed);
+}
@@ -1,4 +1,4 @@
-//! Tests for CLI flags: completions, --markdown-help, --generate-man-pages, --no-generate.
+//! Tests for CLI flags: completions, version, --markdown-help, --generate-man-pages, --no-generate.
 //!
 //! These verify that clap accepts the flags and that the corresponding
 //! code paths produce meaningful output or side effects.
@@ -83,6 +83,38 @@ fn completions_subcommand_parses() {
     ));
 }
 
+// ── version ──────────────────────────────────────────────────────────
+
+#[test]
+fn version_subcommand_parses_json_flag() {
+    let cli = Cli::parse_from(["fmm", "version", "--json"]);
+    assert!(matches!(cli.command, Some(Commands::Version(args)) if args.json));
+}
+
+#[test]
+fn version_json_lists_languages_and_features() {
+    use assert_cmd::cargo::CommandCargoExt;
+
+    let output = std::process::Command::cargo_bin("fmm")
+        .unwrap()
+        .args(["version", "--json"])
+        .output()
+        .expect("failed to run fmm");
+    assert!(output.status.success());
+
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(json["version"], fmm::VERSION);
+    let languages = json["languages"].as_array().unwrap();
+    assert!(
+        languages
+            .iter()
+            .any(|language| language["id"] == "go" && language["extensions"][0] == "go"),
+        "got: {json}"
+    );
+    let features = json["features"].as_array().unwrap();
+    assert!(features.iter().any(|feature| feature == "go-generics"));
+}
+
 // ── --markdown-help ──────────────────────────────────────────────────
 
 #[test]
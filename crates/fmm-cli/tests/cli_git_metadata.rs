@@ -124,7 +124,15 @@ fn generate_no_git_clears_git_metadata() {
     init_git_repo(tmp.path());
 
     fmm::cli::generate(&[path.to_string()], false, false, true).unwrap();
-    fmm::cli::generate_with_git(&[path.to_string()], false, false, true, None, true).unwrap();
+    fmm::cli::generate_with_options(
+        &[path.to_string()],
+        &fmm::cli::GenerateOptions {
+            quiet: true,
+            no_git: true,
+            ..Default::default()
+        },
+    )
+    .unwrap();
 
     assert_eq!(db_meta(tmp.path(), GIT_SHA_META_KEY), None);
     assert_eq!(db_meta(tmp.path(), GIT_BRANCH_META_KEY), None);
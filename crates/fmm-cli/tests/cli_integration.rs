@@ -551,3 +551,301 @@ fn single_file_generate() {
     // Other files should not be indexed
     assert!(!db_indexed(&src_dir, "db.ts"));
 }
+
+#[test]
+fn generate_indexes_other_files_when_one_fails_to_parse() {
+    let tmp = setup_project();
+    let path = tmp.path().to_str().unwrap();
+    // Not valid UTF-8, so the file cannot be read as source.
+    fs::write(
+        tmp.path().join("src/broken.ts"),
+        b"export const \xff = 1;\n",
+    )
+    .unwrap();
+
+    // The failure is returned, not raised, so watch mode and `fmm init` keep going.
+    let summary = fmm::cli::generate(&[path.to_string()], false, false, true).unwrap();
+
+    assert_eq!(summary.errors.len(), 1);
+    assert_eq!(summary.errors[0].file, "src/broken.ts");
+    assert!(db_indexed(tmp.path(), "src/auth.ts"));
+    assert!(db_indexed(tmp.path(), "src/utils.py"));
+    assert!(!db_indexed(tmp.path(), "src/broken.ts"));
+}
+
+#[test]
+fn outline_of_indexed_go_file_shows_package_doc() {
+    let tmp = TempDir::new().unwrap();
+    fs::write(
+        tmp.path().join("server.go"),
+        "// Package server runs the HTTP API.\npackage server\n\nfunc Run() {}\n",
+    )
+    .unwrap();
+    fmm::cli::generate(
+        &[tmp.path().to_str().unwrap().to_string()],
+        false,
+        false,
+        true,
+    )
+    .unwrap();
+
+    let output = Command::cargo_bin("fmm")
+        .unwrap()
+        .current_dir(tmp.path())
+        .args(["outline", "server.go"])
+        .output()
+        .unwrap();
+
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("\npackage_doc: Package server runs the HTTP API.\n"),
+        "got: {stdout}"
+    );
+}
+
+#[test]
+fn outline_shows_go_import_path_with_and_without_go_mod() {
+    let tmp = TempDir::new().unwrap();
+    fs::create_dir_all(tmp.path().join("internal/server")).unwrap();
+    fs::write(tmp.path().join("go.mod"), "module example.com/mod\n").unwrap();
+    fs::write(
+        tmp.path().join("internal/server/server.go"),
+        "package server\n\nfunc Run() {}\n",
+    )
+    .unwrap();
+    fmm::cli::generate(
+        &[tmp.path().to_str().unwrap().to_string()],
+        false,
+        false,
+        true,
+    )
+    .unwrap();
+    let outline = |args: &[&str]| {
+        let output = Command::cargo_bin("fmm")
+            .unwrap()
+            .current_dir(tmp.path())
+            .args(args)
+            .output()
+            .unwrap();
+        assert!(output.status.success());
+        String::from_utf8_lossy(&output.stdout).into_owned()
+    };
+
+    let stdout = outline(&["outline", "internal/server/server.go"]);
+    assert!(
+        stdout.contains("\nimport_path: example.com/mod/internal/server\n"),
+        "got: {stdout}"
+    );
+
+    fs::remove_file(tmp.path().join("go.mod")).unwrap();
+    let stdout = outline(&["outline", "internal/server/server.go"]);
+    assert!(
+        stdout.contains("\nimport_path: internal/server\n"),
+        "got: {stdout}"
+    );
+    let stdout = outline(&["outline", "internal/server/server.go", "--json"]);
+    let json: serde_json::Value = serde_json::from_str(&stdout).unwrap();
+    assert_eq!(json["import_path"], "internal/server");
+}
+
+#[test]
+fn outline_of_go_test_file_marks_tests_and_their_kind() {
+    let tmp = TempDir::new().unwrap();
+    fs::write(
+        tmp.path().join("server_test.go"),
+        "package server_test\n\nimport \"testing\"\n\nfunc TestRun(t *testing.T) {}\n\nfunc BenchmarkRun(b *testing.B) {}\n",
+    )
+    .unwrap();
+    fmm::cli::generate(
+        &[tmp.path().to_str().unwrap().to_string()],
+        false,
+        false,
+        true,
+    )
+    .unwrap();
+
+    let output = Command::cargo_bin("fmm")
+        .unwrap()
+        .current_dir(tmp.path())
+        .args(["outline", "server_test.go"])
+        .output()
+        .unwrap();
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("\npackage: server_test\nimport_path: .\ntest: true\n"),
+        "got: {stdout}"
+    );
+    assert!(
+        stdout.contains("    test_kind: benchmark\n"),
+        "got: {stdout}"
+    );
+
+    let output = Command::cargo_bin("fmm")
+        .unwrap()
+        .current_dir(tmp.path())
+        .args(["outline", "server_test.go", "--json"])
+        .output()
+        .unwrap();
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(json["package"], "server_test");
+    assert_eq!(json["test"], true);
+    let exports: Vec<(&str, bool, &str)> = json["exports"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|export| {
+            (
+                export["name"].as_str().unwrap(),
+                export["test"].as_bool().unwrap(),
+                export["test_kind"].as_str().unwrap(),
+            )
+        })
+        .collect();
+    assert_eq!(
+        exports,
+        vec![
+            ("TestRun", true, "test"),
+            ("BenchmarkRun", true, "benchmark")
+        ]
+    );
+}
+
+#[test]
+fn outline_include_private_labels_go_init_and_main() {
+    let tmp = TempDir::new().unwrap();
+    fs::write(
+        tmp.path().join("main.go"),
+        "package main\n\nfunc init() {}\n\nfunc init() {}\n\nfunc main() {}\n\nfunc run() {}\n",
+    )
+    .unwrap();
+    fmm::cli::generate(
+        &[tmp.path().to_str().unwrap().to_string()],
+        false,
+        false,
+        true,
+    )
+    .unwrap();
+
+    let output = Command::cargo_bin("fmm")
+        .unwrap()
+        .current_dir(tmp.path())
+        .args(["outline", "main.go", "--include-private"])
+        .output()
+        .unwrap();
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert_eq!(
+        stdout.matches("    special: init\n").count(),
+        2,
+        "got: {stdout}"
+    );
+    assert_eq!(
+        stdout.matches("    special: main\n").count(),
+        1,
+        "got: {stdout}"
+    );
+    assert_eq!(stdout.matches("    special:").count(), 3, "got: {stdout}");
+}
+
+#[test]
+fn generate_json_lists_parse_errors_and_exits_non_zero() {
+    let tmp = setup_project();
+    fs::write(
+        tmp.path().join("src/broken.ts"),
+        b"export const \xff = 1;\n",
+    )
+    .unwrap();
+
+    let output = Command::cargo_bin("fmm")
+        .unwrap()
+        .current_dir(tmp.path())
+        .args(["generate", "--no-git", "--json", "."])
+        .output()
+        .unwrap();
+
+    assert!(!output.status.success());
+    let summary: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let errors = summary["errors"].as_array().unwrap();
+    assert_eq!(errors.len(), 1);
+    assert_eq!(errors[0]["file"], "src/broken.ts");
+    assert!(errors[0]["error"].as_str().is_some_and(|e| !e.is_empty()));
+    assert!(summary["indexed"].as_u64().unwrap() > 0);
+    assert!(db_indexed(tmp.path(), "src/auth.ts"));
+}
+
+#[test]
+fn generate_fail_on_parse_error_writes_nothing() {
+    let tmp = setup_project();
+    let path = tmp.path().to_str().unwrap();
+    fs::write(
+        tmp.path().join("src/broken.ts"),
+        b"export const \xff = 1;\n",
+    )
+    .unwrap();
+
+    let error = fmm::cli::generate_with_options(
+        &[path.to_string()],
+        &fmm::cli::GenerateOptions {
+            quiet: true,
+            no_git: true,
+            fail_on_parse_error: true,
+            ..Default::default()
+        },
+    )
+    .unwrap_err()
+    .to_string();
+
+    assert!(error.contains("nothing was written"));
+    assert!(!db_indexed(tmp.path(), "src/auth.ts"));
+}
+
+#[test]
+fn generate_include_and_exclude_globs_compose() {
+    let tmp = setup_project();
+    let path = tmp.path().to_str().unwrap();
+
+    fmm::cli::generate_with_options(
+        &[path.to_string()],
+        &fmm::cli::GenerateOptions {
+            quiet: true,
+            no_git: true,
+            include: vec!["src/**/*.ts".to_string()],
+            exclude: vec!["**/db.ts".to_string()],
+            ..Default::default()
+        },
+    )
+    .unwrap();
+
+    assert!(db_indexed(tmp.path(), "src/auth.ts"));
+    assert!(!db_indexed(tmp.path(), "src/db.ts"));
+    assert!(!db_indexed(tmp.path(), "src/utils.py"));
+}
+
+#[test]
+fn generate_stats_count_the_index_when_nothing_changed() {
+    let tmp = TempDir::new().unwrap();
+    fs::write(
+        tmp.path().join("server.go"),
+        "package server\n\nfunc Serve() {}\n\nfunc helper() {}\n",
+    )
+    .unwrap();
+    let stats = || {
+        let output = Command::cargo_bin("fmm")
+            .unwrap()
+            .current_dir(tmp.path())
+            .args(["generate", "--quiet", "--no-git", "--stats", "."])
+            .output()
+            .unwrap();
+        assert!(output.status.success());
+        String::from_utf8_lossy(&output.stderr).into_owned()
+    };
+
+    let first = stats();
+    assert!(
+        first.contains("1 files · 5 lines · 2 symbols (1 exported, 1 unexported)"),
+        "got: {first}"
+    );
+    assert_eq!(stats(), first, "an up-to-date run reports the same index");
+}
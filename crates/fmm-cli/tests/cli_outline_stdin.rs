@@ -0,0 +1,1064 @@
+use assert_cmd::cargo::CommandCargoExt;
+use std::io::Write;
+use std::process::{Command, Output, Stdio};
+use tempfile::TempDir;
+
+fn run_fmm_with_stdin(root: &std::path::Path, args: &[&str], stdin: &str) -> Output {
+    let mut child = Command::cargo_bin("fmm")
+        .unwrap()
+        .args(args)
+        .current_dir(root)
+        .stdin(Stdio::piped())
+        .stdout(Stdio::piped())
+        .stderr(Stdio::piped())
+        .spawn()
+        .expect("failed to run fmm");
+    child
+        .stdin
+        .take()
+        .unwrap()
+        .write_all(stdin.as_bytes())
+        .unwrap();
+    child.wait_with_output().expect("failed to wait for fmm")
+}
+
+const GO_SOURCE: &str = "package main\n\ntype Server struct {\n\tAddr string\n}\n\nfunc (s *Server) Start() error {\n\treturn nil\n}\n\nfunc Run() {}\n";
+
+#[test]
+fn outline_stdin_go_without_index() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], GO_SOURCE);
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(stdout.contains("file: '<stdin>'"), "got: {stdout}");
+    assert!(stdout.contains("Server:"), "got: {stdout}");
+    assert!(
+        stdout.contains("lines: [11, 11]"),
+        "Run lines; got: {stdout}"
+    );
+    assert!(
+        !tmp.path().join(".fmm.db").exists(),
+        "stdin outline must not create an index"
+    );
+}
+
+#[test]
+fn outline_stdin_json_reports_piped_positions() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        GO_SOURCE,
+    );
+
+    assert!(output.status.success());
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(json["file"], "<stdin>");
+    let exports = json["exports"].as_array().unwrap();
+    let server = exports.iter().find(|e| e["name"] == "Server").unwrap();
+    assert_eq!(server["lines"], serde_json::json!([3, 5]));
+    let start = server["members"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .find(|m| m["name"] == "Start")
+        .unwrap();
+    assert_eq!(start["lines"], serde_json::json!([7, 9]));
+}
+
+#[test]
+fn outline_stdin_context_adds_body_snippets_to_functions() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json", "--context", "1"],
+        GO_SOURCE,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let exports = json["exports"].as_array().unwrap();
+    let server = exports.iter().find(|e| e["name"] == "Server").unwrap();
+    assert!(server.get("snippet").is_none(), "types carry no snippet");
+    let start = server["members"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .find(|m| m["name"] == "Start")
+        .unwrap();
+    assert_eq!(start["snippet"], "\treturn nil");
+    let run = exports.iter().find(|e| e["name"] == "Run").unwrap();
+    assert!(
+        run.get("snippet").is_none(),
+        "empty bodies carry no snippet"
+    );
+}
+
+#[cfg(unix)]
+#[test]
+fn outline_stdin_annotate_cmd_merges_objects_by_symbol_name() {
+    let tmp = TempDir::new().unwrap();
+    // Answers `["Server", ...]` with `[{"owner":"Server"}, ...]`.
+    let command = r#"sed 's/"\([^"]*\)"/{"owner":"\1"}/g'"#;
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[
+            "outline",
+            "-",
+            "--lang",
+            "go",
+            "--json",
+            "--annotate-cmd",
+            command,
+        ],
+        GO_SOURCE,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let exports = json["exports"].as_array().unwrap();
+    let server = exports.iter().find(|e| e["name"] == "Server").unwrap();
+    assert_eq!(
+        server["annotations"],
+        serde_json::json!({"owner": "Server"})
+    );
+    let start = server["members"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .find(|m| m["name"] == "Start")
+        .unwrap();
+    assert_eq!(
+        start["annotations"],
+        serde_json::json!({"owner": "Server.Start"})
+    );
+}
+
+#[cfg(unix)]
+#[test]
+fn outline_stdin_annotate_cmd_failure_warns_and_prints_outline() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[
+            "outline",
+            "-",
+            "--lang",
+            "go",
+            "--json",
+            "--annotate-cmd",
+            "echo not-json",
+        ],
+        GO_SOURCE,
+    );
+
+    assert!(output.status.success());
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("--annotate-cmd"), "stderr: {stderr}");
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let exports = json["exports"].as_array().unwrap();
+    assert!(exports.iter().any(|e| e["name"] == "Run"));
+    assert!(exports.iter().all(|e| e.get("annotations").is_none()));
+}
+
+#[test]
+fn outline_stdin_min_complexity_keeps_branchy_functions() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\ntype Server struct{}\n\nfunc (s *Server) Start(ok bool) error {\n\tif ok {\n\t\treturn nil\n\t}\n\treturn nil\n}\n\nfunc (s *Server) Stop() {}\n\nfunc Run() {}\n";
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[
+            "outline",
+            "-",
+            "--lang",
+            "go",
+            "--json",
+            "--min-complexity",
+            "2",
+        ],
+        source,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let exports = json["exports"].as_array().unwrap();
+    let names: Vec<&str> = exports.iter().filter_map(|e| e["name"].as_str()).collect();
+    assert_eq!(names, vec!["Server"], "Run scores 1");
+    let members = exports[0]["members"].as_array().unwrap();
+    assert_eq!(members.len(), 1, "Stop scores 1; got: {members:?}");
+    assert_eq!(members[0]["name"], "Start");
+    assert_eq!(members[0]["complexity"], 2);
+}
+
+#[test]
+fn outline_stdin_markers_attach_to_functions_and_package_scope() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\n// TODO: split this file\n\nfunc Run() {\n\t// FIXME: retry\n\t/* XXX: racy */\n}\n\nfunc helper() {\n\t// HACK: skip auth\n}\n";
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[
+            "outline",
+            "-",
+            "--lang",
+            "go",
+            "--json",
+            "--markers",
+            "--marker-tag",
+            "XXX",
+        ],
+        source,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let run = &json["exports"][0];
+    assert_eq!(run["name"], "Run");
+    assert_eq!(
+        run["markers"],
+        serde_json::json!([
+            { "tag": "FIXME", "line": 6, "text": "retry" },
+            { "tag": "XXX", "line": 7, "text": "racy" },
+        ])
+    );
+    assert_eq!(
+        json["markers"],
+        serde_json::json!([
+            { "tag": "TODO", "line": 3, "text": "split this file" },
+            { "tag": "HACK", "line": 11, "text": "skip auth", "function": "helper" },
+        ])
+    );
+}
+
+#[test]
+fn outline_stdin_eval_consts_adds_folded_values() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\nimport \"time\"\n\nconst KiB = 1 << 10\n\nconst Greeting = \"a\" + \"b\"\n\nconst Timeout = 5 * time.Second\n";
+    let args = ["outline", "-", "--lang", "go", "--json"];
+
+    let output = run_fmm_with_stdin(tmp.path(), &args, source);
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert!(
+        json["exports"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .all(|e| e.get("value").is_none()),
+        "values need --eval-consts"
+    );
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[&args[..], &["--eval-consts"]].concat(),
+        source,
+    );
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let value = |name: &str| {
+        json["exports"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .find(|e| e["name"] == name)
+            .unwrap()["value"]
+            .clone()
+    };
+    assert_eq!(value("KiB"), "1024");
+    assert_eq!(value("Greeting"), "\"ab\"");
+    assert_eq!(value("Timeout"), "5 * time.Second");
+}
+
+#[test]
+fn outline_stdin_only_deprecated_lists_deprecated_symbols() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\n// Dial connects.\n//\n// Deprecated: Use DialContext.\nfunc Dial() {}\n\n// DialContext connects. Deprecated: is only a word here.\nfunc DialContext() {}\n\ntype Client struct{}\n\n// Deprecated:\nfunc (c *Client) Close() {}\n\nfunc (c *Client) Send() {}\n";
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[
+            "outline",
+            "-",
+            "--lang",
+            "go",
+            "--json",
+            "--only-deprecated",
+        ],
+        source,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let exports = json["exports"].as_array().unwrap();
+    let names: Vec<&str> = exports
+        .iter()
+        .map(|e| e["name"].as_str().unwrap())
+        .collect();
+    assert_eq!(names, vec!["Dial", "Client"]);
+    assert_eq!(exports[0]["deprecated"], true);
+    assert_eq!(exports[0]["deprecation_note"], "Use DialContext.");
+    assert!(exports[1].get("deprecated").is_none());
+    let members = exports[1]["members"].as_array().unwrap();
+    assert_eq!(members.len(), 1);
+    assert_eq!(members[0]["name"], "Close");
+    assert_eq!(members[0]["deprecated"], true);
+    assert!(members[0].get("deprecation_note").is_none());
+}
+
+#[test]
+fn outline_stdin_callgraph_prints_dot() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\ntype Handler struct{}\n\nfunc (h *Handler) Serve() {\n\th.validate()\n}\n\nfunc (h *Handler) validate() {\n\t_ = helper()\n}\n\nfunc helper() int {\n\treturn helper()\n}\n";
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--callgraph"],
+        source,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(stdout.starts_with("digraph \"<stdin>\" {"), "got: {stdout}");
+    for line in [
+        "    \"Handler.Serve\";",
+        "    \"Handler.Serve\" -> \"Handler.validate\";",
+        "    \"Handler.validate\" -> \"helper\";",
+        "    \"helper\" -> \"helper\";",
+    ] {
+        assert!(
+            stdout.lines().any(|l| l == line),
+            "missing {line}; got: {stdout}"
+        );
+    }
+    assert!(stdout.trim_end().ends_with('}'), "got: {stdout}");
+}
+
+#[test]
+fn outline_stdin_undocumented_fails_above_threshold() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\nconst MaxRetries = 3\n\n// Handler serves requests.\ntype Handler struct{}\n\ntype Config struct{}\n";
+    let args = ["outline", "-", "--lang", "go", "--undocumented"];
+
+    let output = run_fmm_with_stdin(tmp.path(), &args, source);
+    assert!(!output.status.success(), "two undocumented, none allowed");
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert_eq!(
+        stdout.lines().collect::<Vec<_>>(),
+        vec!["MaxRetries", "Config"]
+    );
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("--max-undocumented 0"), "got: {stderr}");
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[&args[..], &["--max-undocumented", "2", "--json"]].concat(),
+        source,
+    );
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(
+        json["undocumented"],
+        serde_json::json!(["MaxRetries", "Config"])
+    );
+}
+
+#[test]
+fn outline_stdin_receiver_report_lists_mixed_receivers() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\ntype Handler struct{}\n\nfunc (h *Handler) Start() {}\n\nfunc (h Handler) String() string { return \"\" }\n\ntype Config struct{}\n\nfunc (c *Config) Load() {}\n\nfunc (c *Config) Save() {}\n";
+    let args = ["outline", "-", "--lang", "go", "--receiver-report"];
+
+    let output = run_fmm_with_stdin(tmp.path(), &args, source);
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert_eq!(
+        stdout.lines().collect::<Vec<_>>(),
+        vec!["Handler", "  Start: h *Handler", "  String: h Handler"]
+    );
+
+    let output = run_fmm_with_stdin(tmp.path(), &[&args[..], &["--json"]].concat(), source);
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(
+        json["inconsistent_receivers"],
+        serde_json::json!({"Handler": {"Start": "h *Handler", "String": "h Handler"}})
+    );
+}
+
+#[test]
+fn outline_stdin_imports_only_lists_kinds_and_unused() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\nimport (\n\t\"fmt\"\n\t\"net/http\"\n\n\t\"github.com/gin-gonic/gin\"\n)\n\nfunc Run() {\n\tfmt.Println(gin.Version)\n}\n";
+    let args = ["outline", "-", "--lang", "go", "--imports-only"];
+
+    let output = run_fmm_with_stdin(tmp.path(), &args, source);
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert_eq!(
+        stdout.lines().collect::<Vec<_>>(),
+        vec![
+            "fmt stdlib",
+            "github.com/gin-gonic/gin third_party",
+            "net/http stdlib unused",
+        ]
+    );
+
+    let output = run_fmm_with_stdin(tmp.path(), &[&args[..], &["--json"]].concat(), source);
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert!(json.get("exports").is_none());
+    assert_eq!(
+        json["imports"][2],
+        serde_json::json!({"path": "net/http", "kind": "stdlib", "used": false})
+    );
+}
+
+#[test]
+fn outline_stdin_layout_prints_offsets_padding_and_suggested_order() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\nimport \"net/http\"\n\ntype Config struct {\n\tHost  string\n\tPort  int\n\tDebug bool\n}\n\ntype Flags struct {\n\tA bool\n\tB int64\n\tC bool\n}\n\ntype Remote struct {\n\tClient *http.Client\n\tServer http.Server\n}\n";
+    let args = ["outline", "-", "--lang", "go", "--layout"];
+
+    let output = run_fmm_with_stdin(tmp.path(), &args, source);
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    // Expected sizes assume a 64-bit host.
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert_eq!(
+        stdout.lines().collect::<Vec<_>>(),
+        vec![
+            "Config: size 32, align 8",
+            "  Host: offset 0, size 16",
+            "  Port: offset 16, size 8",
+            "  Debug: offset 24, size 1, padding 7",
+            "Flags: size 24, align 8",
+            "  A: offset 0, size 1, padding 7",
+            "  B: offset 8, size 8",
+            "  C: offset 16, size 1, padding 7",
+            "  suggested order: B, A, C (size 16)",
+            "Remote: size unknown because of Server",
+        ]
+    );
+
+    let output = run_fmm_with_stdin(tmp.path(), &[&args[..], &["--json"]].concat(), source);
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(json["struct_layout"]["Flags"]["suggested_size"], 16);
+}
+
+#[test]
+fn outline_stdin_error_report_lists_error_results() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\ntype Handler struct{}\n\nfunc NewHandler() *Handler { return nil }\n\nfunc (h *Handler) Validate() error { return nil }\n\nfunc Lookup(key string) (error, bool) { return nil, false }\n";
+    let args = ["outline", "-", "--lang", "go"];
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[&args[..], &["--error-report"]].concat(),
+        source,
+    );
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert_eq!(
+        stdout.lines().collect::<Vec<_>>(),
+        vec![
+            "Handler.Validate: result 0 (last)",
+            "Lookup: result 0 (not last)"
+        ]
+    );
+
+    let output = run_fmm_with_stdin(tmp.path(), &[&args[..], &["--json"]].concat(), source);
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let export = |name: &str| {
+        json["exports"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .find(|e| e["name"] == name)
+            .unwrap()
+            .clone()
+    };
+    assert_eq!(export("NewHandler")["error_return"], false);
+    assert!(export("NewHandler").get("error_return_position").is_none());
+    assert_eq!(export("Lookup")["error_return_position"], 0);
+    assert!(export("Handler").get("error_return").is_none());
+    let validate = &export("Handler")["members"][0];
+    assert_eq!(validate["name"], "Validate");
+    assert_eq!(validate["error_return"], true);
+}
+
+#[test]
+fn outline_stdin_shadow_report_lists_shadowed_package_names() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\nvar config = 1\n\ntype Handler struct{}\n\nfunc (h *Handler) Serve() {\n\tif config := 2; config > 1 {\n\t\t_ = config\n\t}\n}\n\nfunc run(Handler int) {}\n";
+    let args = ["outline", "-", "--lang", "go", "--shadow-report"];
+
+    let output = run_fmm_with_stdin(tmp.path(), &args, source);
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert_eq!(
+        stdout.lines().collect::<Vec<_>>(),
+        vec![
+            "Handler.Serve: config at line 8 shadows line 3",
+            "run: Handler at line 13 shadows line 5",
+        ]
+    );
+
+    let output = run_fmm_with_stdin(tmp.path(), &[&args[..], &["--json"]].concat(), source);
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(
+        json["shadowed"][0],
+        serde_json::json!({"function": "Handler.Serve", "name": "config", "line": 8, "declared_line": 3})
+    );
+}
+
+#[test]
+fn outline_stdin_report_flags_conflict_with_outline_formats() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--callgraph", "--tree"],
+        GO_SOURCE,
+    );
+
+    assert!(!output.status.success());
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("cannot be used with"), "got: {stderr}");
+}
+
+#[test]
+fn outline_stdin_go_syntax_error_exits_non_zero() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go"],
+        "package main\n\nfunc Run() {\n\tx := )\n}\n",
+    );
+
+    assert!(!output.status.success());
+    assert!(output.stdout.is_empty(), "no partial outline on stdout");
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("<stdin>:4:"), "got: {stderr}");
+}
+
+#[test]
+fn outline_stdin_python_syntax_error_exits_non_zero() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "py"],
+        "def run():\n    return 1\n\ndef broken(:\n    pass\n",
+    );
+
+    assert!(!output.status.success());
+    assert!(output.stdout.is_empty(), "no partial outline on stdout");
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("<stdin>:4:"), "got: {stderr}");
+}
+
+#[test]
+fn outline_stdin_signature_only_prints_one_line_per_symbol() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\nimport \"time\"\n\nconst MaxRetries = 3\n\nconst Timeout = 5 * time.Second\n\ntype Server struct {\n\tAddr string\n}\n\nfunc (s *Server) Start() error {\n\treturn nil\n}\n";
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--signature-only"],
+        source,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    assert_eq!(
+        String::from_utf8_lossy(&output.stdout),
+        "const MaxRetries = 3\nconst Timeout\ntype Server struct\n  Addr string\n  func (s *Server) Start() error\n"
+    );
+}
+
+#[test]
+fn outline_stdin_function_signatures_match_across_formats() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\nfunc Printf(prefix string, args ...any) (n int, err error) {\n\treturn 0, nil\n}\n\nfunc Close() error {\n\treturn nil\n}\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Discard(_ string, _ int) {}\n";
+    let signatures = [
+        "func Printf(prefix string, args ...any) (n int, err error)",
+        "func Close() error",
+        "func Add(a, b int) int",
+        "func Discard(_ string, _ int)",
+    ];
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--signature-only"],
+        source,
+    );
+    assert!(output.status.success());
+    assert_eq!(
+        String::from_utf8_lossy(&output.stdout),
+        format!("{}\n", signatures.join("\n"))
+    );
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        source,
+    );
+    assert!(output.status.success());
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let json_signatures: Vec<&str> = json["exports"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|e| e["signature"].as_str().unwrap())
+        .collect();
+    assert_eq!(json_signatures, signatures);
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let yaml = String::from_utf8_lossy(&output.stdout);
+    for signature in signatures {
+        assert!(yaml.contains(signature), "{signature} missing from: {yaml}");
+    }
+}
+
+#[test]
+fn outline_stdin_sort_name_orders_symbols_and_members() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\ntype Server struct {\n\tPort int\n\tAddr string\n}\n\nfunc Run() {}\n\nconst Version = \"1\"\n";
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[
+            "outline",
+            "-",
+            "--lang",
+            "go",
+            "--signature-only",
+            "--sort",
+            "name",
+        ],
+        source,
+    );
+    assert!(output.status.success());
+    assert_eq!(
+        String::from_utf8_lossy(&output.stdout),
+        "func Run()\ntype Server struct\n  Addr string\n  Port int\nconst Version = \"1\"\n"
+    );
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json", "--sort", "kind"],
+        source,
+    );
+    assert!(output.status.success());
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let names: Vec<&str> = json["exports"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|e| e["name"].as_str().unwrap())
+        .collect();
+    assert_eq!(names, vec!["Version", "Run", "Server"]);
+}
+
+#[test]
+fn outline_stdin_requires_lang() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-"], GO_SOURCE);
+
+    assert!(!output.status.success());
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("--lang"), "got: {stderr}");
+}
+
+#[test]
+fn outline_stdin_tree_draws_members_as_branches() {
+    let tmp = TempDir::new().unwrap();
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--tree", "--no-color"],
+        GO_SOURCE,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    assert_eq!(
+        String::from_utf8_lossy(&output.stdout),
+        "<stdin>\n\
+         ├── Server struct [3, 5]\n\
+         │   ├── Addr field [4, 4]\n\
+         │   └── Start method [7, 9]\n\
+         └── Run fn [11, 11]\n"
+    );
+}
+
+#[test]
+fn outline_stdin_shows_package_doc() {
+    let tmp = TempDir::new().unwrap();
+    let source = "// Package server runs the HTTP API.\n//\n// Start it with Run.\npackage server\n\nfunc Run() {}\n";
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains(
+            "package_doc: |\n  Package server runs the HTTP API.\n\n  Start it with Run.\n"
+        ),
+        "got: {stdout}"
+    );
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        source,
+    );
+    assert!(output.status.success());
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(
+        json["package_doc"],
+        "Package server runs the HTTP API.\n\nStart it with Run."
+    );
+}
+
+#[test]
+fn outline_stdin_qualify_imports_expands_package_selectors() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package cache\n\nimport (\n\t\"github.com/redis/go-redis/v9\"\n\t. \"github.com/onsi/gomega\"\n)\n\ntype Store struct {\n\tClient *redis.Client\n}\n\nfunc Open(addr string) (*redis.Client, Matcher) {\n\treturn nil, nil\n}\n";
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &[
+            "outline",
+            "-",
+            "--lang",
+            "go",
+            "--qualify-imports",
+            "--signature-only",
+        ],
+        source,
+    );
+
+    assert!(
+        output.status.success(),
+        "stderr: {}",
+        String::from_utf8_lossy(&output.stderr)
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("  Client *github.com/redis/go-redis/v9.Client\n"),
+        "got: {stdout}"
+    );
+    assert!(
+        stdout.contains("func Open(addr string) (*github.com/redis/go-redis/v9.Client, Matcher)\n"),
+        "got: {stdout}"
+    );
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(stdout.contains("*redis.Client"), "got: {stdout}");
+    assert!(!stdout.contains("v9.Client"), "got: {stdout}");
+}
+
+#[test]
+fn outline_stdin_marks_constants_with_their_block() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package main\n\ntype Status int\n\nconst (\n\tStatusActive Status = iota\n\tStatusInactive\n)\n\nconst Single = 1\n";
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("  StatusInactive:\n    lines: [7, 7]\n    size: 1\n    signature: const StatusInactive Status = iota\n    visibility: public\n    kind: const\n    block: [5, 8]\n"),
+        "got: {stdout}"
+    );
+    assert_eq!(stdout.matches("block: [5, 8]").count(), 2, "got: {stdout}");
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        source,
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let block = |name: &str| {
+        json["exports"]
+            .as_array()
+            .unwrap()
+            .iter()
+            .find(|export| export["name"] == name)
+            .unwrap()["block"]
+            .clone()
+    };
+    assert_eq!(block("StatusActive"), serde_json::json!([5, 8]));
+    assert_eq!(block("StatusInactive"), serde_json::json!([5, 8]));
+    assert_eq!(block("Single"), serde_json::Value::Null);
+}
+
+#[test]
+fn outline_stdin_labels_embedded_fields() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package server\n\ntype Server struct {\n\t*Base\n\tio.Reader\n\tName string\n}\n";
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("      - name: Base\n        lines: [4, 4]\n        size: 1\n"),
+        "got: {stdout}"
+    );
+    assert_eq!(
+        stdout
+            .matches("        kind: field\n        embedded: true\n")
+            .count(),
+        2,
+        "got: {stdout}"
+    );
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        source,
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let embedded: Vec<(&str, bool)> = json["exports"][0]["members"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|member| {
+            (
+                member["name"].as_str().unwrap(),
+                member["embedded"].as_bool().unwrap_or(false),
+            )
+        })
+        .collect();
+    assert_eq!(
+        embedded,
+        vec![("Base", true), ("Reader", true), ("Name", false)]
+    );
+}
+
+#[test]
+fn outline_stdin_expand_embeds_lists_promoted_interface_methods() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package store\n\ntype Closer interface {\n\tClose() error\n}\n\ntype ReadCloser interface {\n\tio.Reader\n\tCloser\n\tPeek() byte\n}\n";
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("    embeds: [io.Reader, Closer]\n"),
+        "got: {stdout}"
+    );
+    assert!(!stdout.contains("promoted_from"), "got: {stdout}");
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--expand-embeds"],
+        source,
+    );
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("        promoted_from: Closer\n"),
+        "got: {stdout}"
+    );
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--expand-embeds", "--json"],
+        source,
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let read_closer = &json["exports"][1];
+    assert_eq!(
+        read_closer["embeds"],
+        serde_json::json!(["io.Reader", "Closer"])
+    );
+    let members: Vec<(&str, Option<&str>)> = read_closer["members"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|member| {
+            (
+                member["name"].as_str().unwrap(),
+                member["promoted_from"].as_str(),
+            )
+        })
+        .collect();
+    assert_eq!(members, vec![("Close", Some("Closer")), ("Peek", None)]);
+}
+
+#[test]
+fn outline_stdin_labels_test_main_as_special() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package server_test\n\nimport \"testing\"\n\nfunc TestMain(m *testing.M) {}\n";
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(stdout.contains("    special: test_main\n"), "got: {stdout}");
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        source,
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    assert_eq!(json["exports"][0]["name"], "TestMain");
+    assert_eq!(json["exports"][0]["special"], "test_main");
+}
+
+#[test]
+fn outline_stdin_notes_methods_on_types_declared_elsewhere() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package store\n\nfunc (c *Client) Retry() {}\n\nfunc Open() {}\n";
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("    kind: method\n    loc: 0\n    external_receiver: Client\n"),
+        "got: {stdout}"
+    );
+    assert_eq!(
+        stdout.matches("external_receiver").count(),
+        1,
+        "got: {stdout}"
+    );
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        source,
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let receivers: Vec<(&str, Option<&str>)> = json["exports"]
+        .as_array()
+        .unwrap()
+        .iter()
+        .map(|export| {
+            (
+                export["name"].as_str().unwrap(),
+                export["external_receiver"].as_str(),
+            )
+        })
+        .collect();
+    assert_eq!(receivers, vec![("Retry", Some("Client")), ("Open", None)]);
+}
+
+#[test]
+fn outline_stdin_loc_counts_body_lines_not_the_signature() {
+    let tmp = TempDir::new().unwrap();
+    let source = "package store\n\nfunc Process(\n\tctx context.Context,\n\tid string,\n) error {\n\tvalidate(id)\n\treturn nil\n}\n\ntype Reader interface {\n\tRead(p []byte) (int, error)\n}\n";
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        stdout.contains("  Process:\n    lines: [3, 9]\n    size: 7\n"),
+        "got: {stdout}"
+    );
+    assert!(
+        stdout.contains("    kind: fn\n    loc: 2\n"),
+        "got: {stdout}"
+    );
+    assert!(stdout.contains("        loc: 0\n"), "got: {stdout}");
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--json"],
+        source,
+    );
+    let json: serde_json::Value = serde_json::from_slice(&output.stdout).unwrap();
+    let process = &json["exports"][0];
+    assert_eq!(process["name"], "Process");
+    assert_eq!(process["size"], 7);
+    assert_eq!(process["loc"], 2);
+    let read = &json["exports"][1]["members"][0];
+    assert_eq!(read["name"], "Read");
+    assert_eq!(read["loc"], 0);
+}
+
+#[test]
+fn outline_stdin_takes_flag_defaults_from_fmmrc() {
+    let tmp = TempDir::new().unwrap();
+    std::fs::write(
+        tmp.path().join(".fmmrc.toml"),
+        "[defaults.outline]\nsort = \"name\"\nsortt = \"kind\"\n",
+    )
+    .unwrap();
+    let source = "package server\n\nfunc Zeta() {}\n\nfunc Alpha() {}\n";
+    let position = |stdout: &str, name: &str| stdout.find(&format!("  {name}:\n")).unwrap();
+
+    let output = run_fmm_with_stdin(tmp.path(), &["outline", "-", "--lang", "go"], source);
+    assert!(output.status.success());
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        position(&stdout, "Alpha") < position(&stdout, "Zeta"),
+        "got: {stdout}"
+    );
+    let stderr = String::from_utf8_lossy(&output.stderr);
+    assert!(stderr.contains("`defaults.outline.sortt`"), "got: {stderr}");
+
+    let output = run_fmm_with_stdin(
+        tmp.path(),
+        &["outline", "-", "--lang", "go", "--sort", "source"],
+        source,
+    );
+    let stdout = String::from_utf8_lossy(&output.stdout);
+    assert!(
+        position(&stdout, "Zeta") < position(&stdout, "Alpha"),
+        "got: {stdout}"
+    );
+}
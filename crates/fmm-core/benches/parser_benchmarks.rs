@@ -1,10 +1,12 @@
 use criterion::{Criterion, black_box, criterion_group, criterion_main};
 use fmm_core::manifest::Manifest;
+use fmm_core::parser::builtin::go::GoParser;
 use fmm_core::parser::builtin::python::PythonParser;
 use fmm_core::parser::builtin::rust::RustParser;
 use fmm_core::parser::builtin::typescript::TypeScriptParser;
 use fmm_core::parser::{ExportEntry, Metadata, Parser};
 use fmm_core::search::dependency_graph;
+use rayon::prelude::*;
 use std::time::Duration;
 
 const TYPESCRIPT_SOURCE: &str = r#"
@@ -224,6 +226,11 @@ pub fn process_batch(items: &[String]) -> Result<Vec<String>> {
 }
 "#;
 
+const GO_SOURCE: &str = include_str!(concat!(
+    env!("CARGO_MANIFEST_DIR"),
+    "/../../fixtures/sample.go"
+));
+
 fn bench_parse_typescript(c: &mut Criterion) {
     c.bench_function("parse_typescript_single", |b| {
         let mut parser = TypeScriptParser::new().unwrap();
@@ -251,6 +258,15 @@ fn bench_parse_rust(c: &mut Criterion) {
     });
 }
 
+fn bench_parse_go(c: &mut Criterion) {
+    c.bench_function("parse_go_single", |b| {
+        let mut parser = GoParser::new().unwrap();
+        b.iter(|| {
+            parser.parse(black_box(GO_SOURCE)).unwrap();
+        });
+    });
+}
+
 fn bench_batch_100(c: &mut Criterion) {
     let mut group = c.benchmark_group("batch_100");
     group.sample_size(10);
@@ -299,6 +315,35 @@ fn bench_batch_1000(c: &mut Criterion) {
     group.finish();
 }
 
+/// Sequential vs rayon `map_init` parsing of 300 Go files, mirroring the
+/// one-parser-per-worker strategy used by `fmm generate`.
+fn bench_go_batch_300_parallel(c: &mut Criterion) {
+    let sources: Vec<&str> = vec![GO_SOURCE; 300];
+    let mut group = c.benchmark_group("go_batch_300");
+    group.sample_size(10);
+    group.measurement_time(Duration::from_secs(5));
+    group.bench_function("sequential", |b| {
+        let mut parser = GoParser::new().unwrap();
+        b.iter(|| {
+            for source in &sources {
+                parser.parse(black_box(source)).unwrap();
+            }
+        });
+    });
+    group.bench_function("parallel", |b| {
+        b.iter(|| {
+            sources
+                .par_iter()
+                .map_init(
+                    || GoParser::new().unwrap(),
+                    |parser, source| parser.parse(black_box(source)).unwrap(),
+                )
+                .collect::<Vec<_>>()
+        });
+    });
+    group.finish();
+}
+
 /// Build a synthetic manifest with `n` files in a hub-and-spoke pattern:
 ///
 /// - `core/base.ts` is the hub (imported by all spoke files)
@@ -365,8 +410,10 @@ criterion_group!(
     bench_parse_typescript,
     bench_parse_python,
     bench_parse_rust,
+    bench_parse_go,
     bench_batch_100,
     bench_batch_1000,
+    bench_go_batch_300_parallel,
     bench_dependency_graph_large,
 );
 criterion_main!(benches);
@@ -1,13 +1,14 @@
 use serde::Deserialize;
-use std::collections::BTreeSet;
+use std::collections::{BTreeMap, BTreeSet};
 use tracing::warn;
 
-use super::Config;
+use super::{Config, FlagDefault};
 
 /// Intermediate deserialization target for `.fmmrc.toml`.
 ///
-/// All fields are `Option` so partial configs are valid.
-/// `deny_unknown_fields` catches typos and stale keys at deserialization time.
+/// All fields are `Option` so partial configs are valid. Unknown keys are
+/// dropped by [`parse_file_config`] before deserialization; `deny_unknown_fields`
+/// still rejects a key listed in [`KNOWN_KEYS`] that has no field here.
 #[derive(Deserialize, Default)]
 #[serde(deny_unknown_fields)]
 pub(super) struct FileConfig {
@@ -15,6 +16,11 @@ pub(super) struct FileConfig {
     test_patterns: Option<FileTestPatterns>,
     max_lines: Option<usize>,
     exclude: Option<Vec<String>>,
+    include: Option<Vec<String>>,
+    /// Read by hand from the `[defaults]` table, whose keys are command and
+    /// flag names rather than fields.
+    #[serde(skip)]
+    defaults: BTreeMap<String, BTreeMap<String, FlagDefault>>,
 }
 
 /// Intermediate deserialization target for the `[test_patterns]` TOML section.
@@ -25,6 +31,113 @@ struct FileTestPatterns {
     filename_suffixes: Option<Vec<String>>,
 }
 
+/// Top-level keys of `.fmmrc.toml`, one per `FileConfig` field.
+const KNOWN_KEYS: &[&str] = &[
+    "languages",
+    "test_patterns",
+    "max_lines",
+    "exclude",
+    "include",
+    "defaults",
+];
+
+/// Keys of the `[test_patterns]` table, one per `FileTestPatterns` field.
+const KNOWN_TEST_PATTERN_KEYS: &[&str] = &["path_contains", "filename_suffixes"];
+
+/// Parse `.fmmrc.toml`, dropping keys fmm does not know so a typo costs only
+/// that setting rather than the whole file. Returns the dropped keys as well,
+/// dotted when they sit in a table (`test_patterns.suffixes`).
+pub(super) fn parse_file_config(
+    content: &str,
+) -> Result<(FileConfig, Vec<String>), toml::de::Error> {
+    let mut table: toml::Table = toml::from_str(content)?;
+    let mut unknown = take_unknown_keys(&mut table, KNOWN_KEYS, "");
+    if let Some(toml::Value::Table(test_patterns)) = table.get_mut("test_patterns") {
+        unknown.extend(take_unknown_keys(
+            test_patterns,
+            KNOWN_TEST_PATTERN_KEYS,
+            "test_patterns.",
+        ));
+    }
+    let defaults = match table.remove("defaults") {
+        Some(toml::Value::Table(defaults)) => flag_defaults(defaults, &mut unknown),
+        Some(_) => {
+            unknown.push("defaults".to_string());
+            BTreeMap::new()
+        }
+        None => BTreeMap::new(),
+    };
+    let file_config: FileConfig = toml::Value::Table(table).try_into()?;
+    Ok((
+        FileConfig {
+            defaults,
+            ..file_config
+        },
+        unknown,
+    ))
+}
+
+/// The `[defaults.<command>]` tables. A command that is not a table, or a
+/// flag whose value is neither a scalar nor an array of scalars, is dropped
+/// into `unknown` as `defaults.<command>` or `defaults.<command>.<flag>`.
+fn flag_defaults(
+    table: toml::Table,
+    unknown: &mut Vec<String>,
+) -> BTreeMap<String, BTreeMap<String, FlagDefault>> {
+    let mut by_command = BTreeMap::new();
+    for (command, flags) in table {
+        let toml::Value::Table(flags) = flags else {
+            unknown.push(format!("defaults.{command}"));
+            continue;
+        };
+        let mut defaults = BTreeMap::new();
+        for (flag, value) in flags {
+            let value = match value {
+                toml::Value::Array(values) => values
+                    .iter()
+                    .map(scalar_text)
+                    .collect::<Option<Vec<_>>>()
+                    .map(FlagDefault::Many),
+                value => scalar_text(&value).map(FlagDefault::One),
+            };
+            match value {
+                Some(value) => {
+                    defaults.insert(flag, value);
+                }
+                None => unknown.push(format!("defaults.{command}.{flag}")),
+            }
+        }
+        by_command.insert(command, defaults);
+    }
+    by_command
+}
+
+/// A TOML scalar as it would be typed on the command line: `50`, `name`.
+fn scalar_text(value: &toml::Value) -> Option<String> {
+    match value {
+        toml::Value::String(text) => Some(text.clone()),
+        toml::Value::Integer(n) => Some(n.to_string()),
+        toml::Value::Float(n) => Some(n.to_string()),
+        toml::Value::Boolean(b) => Some(b.to_string()),
+        _ => None,
+    }
+}
+
+fn take_unknown_keys(table: &mut toml::Table, known: &[&str], prefix: &str) -> Vec<String> {
+    let unknown: Vec<String> = table
+        .keys()
+        .filter(|key| !known.contains(&key.as_str()))
+        .cloned()
+        .collect();
+    for key in &unknown {
+        table.remove(key);
+    }
+    unknown
+        .into_iter()
+        .map(|key| format!("{prefix}{key}"))
+        .collect()
+}
+
 pub(super) fn apply_file_config(config: &mut Config, file_config: FileConfig) {
     if let Some(languages) = file_config.languages {
         config.languages = languages;
@@ -35,6 +148,10 @@ pub(super) fn apply_file_config(config: &mut Config, file_config: FileConfig) {
     if let Some(exclude) = file_config.exclude {
         config.exclude = exclude;
     }
+    if let Some(include) = file_config.include {
+        config.include = include;
+    }
+    config.defaults = file_config.defaults;
     if let Some(test_patterns) = file_config.test_patterns {
         // The user took explicit control of test classification: their patterns
         // replace built-in conventions rather than extending them.
@@ -67,6 +184,9 @@ pub(super) fn apply_env_overrides(config: &mut Config) {
     if let Ok(val) = std::env::var("FMM_EXCLUDE") {
         config.exclude = comma_separated_values(&val).collect();
     }
+    if let Ok(val) = std::env::var("FMM_INCLUDE") {
+        config.include = comma_separated_values(&val).collect();
+    }
 }
 
 fn comma_separated_values(val: &str) -> impl Iterator<Item = String> + '_ {
@@ -1,7 +1,7 @@
 use anyhow::Result;
 use serde::{Deserialize, Serialize};
-use std::collections::BTreeSet;
-use std::path::Path;
+use std::collections::{BTreeMap, BTreeSet};
+use std::path::{Path, PathBuf};
 use tracing::warn;
 
 use crate::parser::ParserRegistry;
@@ -120,6 +120,28 @@ pub struct Config {
     /// to .gitignore and .fmmignore rules.
     #[serde(default)]
     pub exclude: Vec<String>,
+    /// Glob patterns relative to project root that limit indexing to matching
+    /// files. Empty means every file not excluded; `exclude` wins over a match.
+    #[serde(default)]
+    pub include: Vec<String>,
+    /// Defaults for command-line flags from the `[defaults.<command>]` tables,
+    /// keyed by command and then by long flag name without dashes. The CLI
+    /// checks the names against its flags; flags given on the command line win.
+    #[serde(default)]
+    pub defaults: BTreeMap<String, BTreeMap<String, FlagDefault>>,
+    /// Keys in `.fmmrc.toml` that fmm does not recognize, such as a misspelled
+    /// setting. They are ignored, and the CLI warns about each by name.
+    #[serde(skip)]
+    pub unknown_keys: Vec<String>,
+}
+
+/// Value of a `[defaults.<command>]` entry: one value, or several for a flag
+/// that can be repeated, such as `fmm exports --kind`.
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(untagged)]
+pub enum FlagDefault {
+    One(String),
+    Many(Vec<String>),
 }
 
 impl Default for Config {
@@ -129,6 +151,9 @@ impl Default for Config {
             test_patterns: TestPatterns::default(),
             max_lines: default_max_lines(),
             exclude: Vec::new(),
+            include: Vec::new(),
+            defaults: BTreeMap::new(),
+            unknown_keys: Vec::new(),
         }
     }
 }
@@ -150,14 +175,44 @@ impl Config {
         Self::load_from_dir(Path::new("."))
     }
 
+    /// The `.fmmrc.toml` that applies to `dir`: the one in `dir` or the
+    /// nearest parent. The search stops at the first directory holding
+    /// `.git`, so a repository never picks up a config from outside it.
+    pub fn find_file(dir: &Path) -> Option<PathBuf> {
+        let dir = dir.canonicalize().unwrap_or_else(|_| dir.to_path_buf());
+        for ancestor in dir.ancestors() {
+            let candidate = ancestor.join(".fmmrc.toml");
+            if candidate.is_file() {
+                return Some(candidate);
+            }
+            if ancestor.join(".git").exists() {
+                break;
+            }
+        }
+        None
+    }
+
+    /// Settings come from built-in defaults, then the `.fmmrc.toml`
+    /// [`find_file`](Self::find_file) picks for `dir`, then `FMM_*` environment
+    /// variables, each overriding the one before. Command line flags such as
+    /// `fmm generate --exclude` apply on top of the result.
     pub fn load_from_dir(dir: &Path) -> Result<Self> {
         let mut config = Self::default();
 
-        let toml_path = dir.join(".fmmrc.toml");
-        if toml_path.exists() {
+        if let Some(toml_path) = Self::find_file(dir) {
             match std::fs::read_to_string(&toml_path) {
-                Ok(content) => match toml::from_str::<loader::FileConfig>(&content) {
-                    Ok(file_config) => loader::apply_file_config(&mut config, file_config),
+                Ok(content) => match loader::parse_file_config(&content) {
+                    Ok((file_config, unknown_keys)) => {
+                        for key in &unknown_keys {
+                            warn!(
+                                path = %toml_path.display(),
+                                key = %key,
+                                "unknown config key; ignoring it"
+                            );
+                        }
+                        loader::apply_file_config(&mut config, file_config);
+                        config.unknown_keys = unknown_keys;
+                    }
                     Err(e) => {
                         warn!(
                             path = %toml_path.display(),
@@ -10,7 +10,12 @@ use std::path::Path;
 use std::sync::{Mutex, MutexGuard};
 use tempfile::TempDir;
 
-const FMM_ENV_KEYS: [&str; 3] = ["FMM_MAX_LINES", "FMM_LANGUAGES", "FMM_EXCLUDE"];
+const FMM_ENV_KEYS: [&str; 4] = [
+    "FMM_MAX_LINES",
+    "FMM_LANGUAGES",
+    "FMM_EXCLUDE",
+    "FMM_INCLUDE",
+];
 static ENV_LOCK: Mutex<()> = Mutex::new(());
 
 struct EnvGuard {
@@ -263,6 +268,18 @@ fn loads_exclude_from_toml() {
     assert_eq!(config.exclude[1], "benchmarks/fixtures/**");
 }
 
+#[test]
+fn loads_include_from_toml() {
+    let tmp = TempDir::new().unwrap();
+    fs::write(
+        tmp.path().join(".fmmrc.toml"),
+        r#"include = ["**/*.go", "internal/**"]"#,
+    )
+    .unwrap();
+    let config = load_clean_from_dir(tmp.path());
+    assert_eq!(config.include, ["**/*.go", "internal/**"]);
+}
+
 #[test]
 fn loads_toml_config_with_languages() {
     let tmp = TempDir::new().unwrap();
@@ -276,7 +293,7 @@ fn loads_toml_config_with_languages() {
 }
 
 #[test]
-fn unknown_keys_fall_back_to_defaults() {
+fn unknown_keys_are_reported_and_the_rest_applied() {
     let tmp = TempDir::new().unwrap();
     fs::write(
         tmp.path().join(".fmmrc.toml"),
@@ -284,7 +301,21 @@ fn unknown_keys_fall_back_to_defaults() {
     )
     .unwrap();
     let config = load_clean_from_dir(tmp.path());
-    assert_eq!(config.languages.len(), 29);
+    assert_eq!(config.languages, BTreeSet::from(["rs".to_string()]));
+    assert_eq!(config.unknown_keys, vec!["bogus_key"]);
+}
+
+#[test]
+fn unknown_test_pattern_keys_are_reported_with_their_table() {
+    let tmp = TempDir::new().unwrap();
+    fs::write(
+        tmp.path().join(".fmmrc.toml"),
+        "[test_patterns]\npath_contains = [\"/spec/\"]\nsuffixes = [\"_spec.rb\"]\n",
+    )
+    .unwrap();
+    let config = load_clean_from_dir(tmp.path());
+    assert_eq!(config.test_patterns.path_contains, vec!["/spec/"]);
+    assert_eq!(config.unknown_keys, vec!["test_patterns.suffixes"]);
 }
 
 #[test]
@@ -399,6 +430,17 @@ fn env_fmm_exclude_overrides_toml() {
     assert_eq!(config.exclude[1], "build/**");
 }
 
+#[test]
+fn env_fmm_include_overrides_toml() {
+    let _env = EnvGuard::new();
+    let tmp = TempDir::new().unwrap();
+    fs::write(tmp.path().join(".fmmrc.toml"), r#"include = ["src/**"]"#).unwrap();
+    // SAFETY: EnvGuard serializes config tests and restores process env.
+    unsafe { std::env::set_var("FMM_INCLUDE", "cmd/**, internal/**") };
+    let config = Config::load_from_dir(tmp.path()).unwrap();
+    assert_eq!(config.include, ["cmd/**", "internal/**"]);
+}
+
 #[test]
 fn env_vars_applied_without_toml_file() {
     let _env = EnvGuard::new();
@@ -473,3 +515,44 @@ fn max_lines_zero_is_valid() {
     let config = load_clean_from_dir(tmp.path());
     assert_eq!(config.max_lines, 0);
 }
+
+#[test]
+fn config_is_found_in_parent_directories_up_to_the_repository() {
+    let tmp = TempDir::new().unwrap();
+    let repo = tmp.path().join("repo");
+    fs::create_dir_all(repo.join(".git")).unwrap();
+    fs::create_dir_all(repo.join("cmd/server")).unwrap();
+    fs::write(repo.join(".fmmrc.toml"), "languages = [\"go\"]\n").unwrap();
+
+    let config = load_clean_from_dir(&repo.join("cmd/server"));
+    assert_eq!(config.languages, BTreeSet::from(["go".to_string()]));
+
+    // A config above the repository root does not apply inside it.
+    fs::remove_file(repo.join(".fmmrc.toml")).unwrap();
+    fs::write(tmp.path().join(".fmmrc.toml"), "languages = [\"go\"]\n").unwrap();
+    assert_eq!(Config::find_file(&repo.join("cmd/server")), None);
+}
+
+#[test]
+fn flag_defaults_are_read_per_command() {
+    let tmp = TempDir::new().unwrap();
+    fs::write(
+        tmp.path().join(".fmmrc.toml"),
+        "[defaults.outline]\nsort = \"godoc\"\n\n[defaults.exports]\nkind = [\"fn\", \"method\"]\nlimit = 50\nfilter = { source = true }\n",
+    )
+    .unwrap();
+    let config = load_clean_from_dir(tmp.path());
+    assert_eq!(
+        config.defaults["outline"]["sort"],
+        FlagDefault::One("godoc".to_string())
+    );
+    assert_eq!(
+        config.defaults["exports"]["kind"],
+        FlagDefault::Many(vec!["fn".to_string(), "method".to_string()])
+    );
+    assert_eq!(
+        config.defaults["exports"]["limit"],
+        FlagDefault::One("50".to_string())
+    );
+    assert_eq!(config.unknown_keys, vec!["defaults.exports.filter"]);
+}
@@ -33,14 +33,27 @@ impl FileProcessor {
     }
 
     /// Single-pass parse: metadata + custom fields from one tree-sitter invocation.
-    fn parse_content(&self, path: &Path, content: &str) -> Result<ParseResult> {
+    ///
+    /// `path` selects the parser by extension and need not exist on disk, so
+    /// in-memory sources (e.g. piped through stdin) parse the same way.
+    pub fn parse_content(&self, path: &Path, content: &str) -> Result<ParseResult> {
+        let mut parser = self.parser_for(path)?;
+        parser.parse_file(content, path)
+    }
+
+    /// First syntax error in `content` as `line:column: message`, using the
+    /// parser `path` selects. `Ok(None)` when it parses cleanly.
+    pub fn syntax_error(&self, path: &Path, content: &str) -> Result<Option<String>> {
+        let mut parser = self.parser_for(path)?;
+        Ok(parser.syntax_error(content))
+    }
+
+    fn parser_for(&self, path: &Path) -> Result<Box<dyn Parser>> {
         let extension = path
             .extension()
             .and_then(|ext| ext.to_str())
             .context("Invalid file extension")?;
-
-        let mut parser = self.registry.get_parser(extension)?;
-        parser.parse_file(content, path)
+        self.registry.get_parser(extension)
     }
 }
 
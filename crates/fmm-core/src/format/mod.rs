@@ -16,9 +16,11 @@ pub use search_formatters::{
     format_bare_search, format_filter_search, format_glossary, format_similar,
 };
 pub use yaml_formatters::{
-    format_class_redirect, format_dependency_cycle_reports, format_dependency_cycles,
-    format_dependency_graph, format_dependency_graph_transitive, format_file_outline,
+    NoteValue, OutlineLayout, OutlineNotes, OutlineSort, format_class_redirect,
+    format_dependency_cycle_reports, format_dependency_cycles, format_dependency_graph,
+    format_dependency_graph_transitive, format_file_outline, format_file_outline_sorted,
     format_lookup_export, format_read_symbol, format_reverse_dependency_graph,
+    sort_outline_symbols,
 };
 
 /// Collapse all runs of whitespace (incl. newlines) to single spaces and trim.
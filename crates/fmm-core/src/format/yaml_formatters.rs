@@ -29,6 +29,220 @@ pub fn format_file_outline(
     top_level_fns: Option<&[TopLevelFunction]>,
     freshness: Option<&str>,
 ) -> String {
+    format_file_outline_sorted(
+        file,
+        entry,
+        reexports,
+        private_by_class,
+        top_level_fns,
+        freshness,
+        &OutlineLayout::default(),
+    )
+}
+
+/// What a caller layers over the indexed outline: the symbol order, and
+/// notes the index does not keep.
+#[derive(Debug, Default)]
+pub struct OutlineLayout {
+    pub sort: OutlineSort,
+    pub notes: OutlineNotes,
+}
+
+/// Extra `key: value` rows for a file outline, usually facts from a fresh
+/// parse of the file. File notes follow `loc:`; symbol notes follow the
+/// symbol's `kind:`.
+#[derive(Debug, Default)]
+pub struct OutlineNotes {
+    file: Vec<(String, NoteValue)>,
+    symbols: HashMap<String, Vec<(String, NoteValue)>>,
+}
+
+/// A note's value: text, written as a `|` block when it spans lines, or an
+/// inline `[a, b]` list.
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub enum NoteValue {
+    Text(String),
+    List(Vec<String>),
+}
+
+impl From<&str> for NoteValue {
+    fn from(text: &str) -> Self {
+        Self::Text(text.to_string())
+    }
+}
+
+impl From<String> for NoteValue {
+    fn from(text: String) -> Self {
+        Self::Text(text)
+    }
+}
+
+impl From<Vec<String>> for NoteValue {
+    fn from(items: Vec<String>) -> Self {
+        Self::List(items)
+    }
+}
+
+impl OutlineNotes {
+    pub fn add_file(&mut self, key: &str, value: impl Into<NoteValue>) {
+        self.file.push((key.to_string(), value.into()));
+    }
+
+    /// Note on the symbol `name`, or on the member `Parent.name`.
+    pub fn add_symbol(&mut self, symbol: &str, key: &str, value: impl Into<NoteValue>) {
+        self.symbols
+            .entry(symbol.to_string())
+            .or_default()
+            .push((key.to_string(), value.into()));
+    }
+
+    fn symbol(&self, symbol: &str) -> &[(String, NoteValue)] {
+        self.symbols.get(symbol).map_or(&[], Vec::as_slice)
+    }
+}
+
+fn push_notes(lines: &mut Vec<String>, indent: usize, notes: &[(String, NoteValue)]) {
+    for (key, value) in notes {
+        let key = format!("{}{}", spaces(indent), yaml_escape(key));
+        match value {
+            NoteValue::Text(text) if text.contains('\n') => {
+                lines.push(format!("{key}: |"));
+                for line in text.lines() {
+                    if line.is_empty() {
+                        lines.push(String::new());
+                    } else {
+                        lines.push(format!("{}{line}", spaces(indent + 2)));
+                    }
+                }
+            }
+            NoteValue::Text(text) => lines.push(format!("{key}: {}", yaml_escape(text))),
+            NoteValue::List(items) => {
+                let items: Vec<String> = items.iter().map(|item| yaml_escape(item)).collect();
+                lines.push(format!("{key}: [{}]", items.join(", ")));
+            }
+        }
+    }
+}
+
+/// Order of symbols in a file outline. Members are ordered within their
+/// parent the same way.
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub enum OutlineSort {
+    /// Source position.
+    #[default]
+    Source,
+    /// Name, compared byte by byte.
+    Name,
+    /// Declaration kind, then name within each kind. Symbols without a kind
+    /// come last.
+    Kind,
+    /// The grouping `go doc` prints: constants and variables in source order,
+    /// then functions by name, then types by name, each followed by its
+    /// constructors. Fields keep source order ahead of methods by name.
+    Godoc,
+}
+
+/// Stable sort of outline symbols by `sort`, given each item's name,
+/// declaration kind and signature. [`OutlineSort::Source`] keeps the order as
+/// given.
+pub fn sort_outline_symbols<T>(
+    items: &mut [T],
+    sort: OutlineSort,
+    key: impl Fn(&T) -> (&str, Option<&str>, Option<&str>),
+) {
+    match sort {
+        OutlineSort::Source => {}
+        OutlineSort::Name => items.sort_by(|a, b| key(a).0.cmp(key(b).0)),
+        OutlineSort::Kind => items.sort_by(|a, b| {
+            let (a_name, a_kind, _) = key(a);
+            let (b_name, b_kind, _) = key(b);
+            a_kind
+                .is_none()
+                .cmp(&b_kind.is_none())
+                .then_with(|| a_kind.cmp(&b_kind))
+                .then_with(|| a_name.cmp(b_name))
+        }),
+        OutlineSort::Godoc => {
+            let types: HashSet<String> = items
+                .iter()
+                .map(&key)
+                .filter(|(_, kind, _)| is_type_kind(*kind))
+                .map(|(name, _, _)| name.to_string())
+                .collect();
+            items.sort_by_cached_key(|item| godoc_key(key(item), &types));
+        }
+    }
+}
+
+fn is_type_kind(kind: Option<&str>) -> bool {
+    matches!(kind, Some("struct" | "trait" | "type" | "enum"))
+}
+
+/// `(group, type, constructor, name)`. Constants, variables and fields leave
+/// the names empty so the stable sort keeps their source order.
+fn godoc_key(
+    (name, kind, signature): (&str, Option<&str>, Option<&str>),
+    types: &HashSet<String>,
+) -> (u8, String, bool, String) {
+    match kind {
+        Some("var") => (1, String::new(), false, String::new()),
+        Some("const" | "field") => (0, String::new(), false, String::new()),
+        _ if is_type_kind(kind) => (3, name.to_string(), false, String::new()),
+        Some("fn") => match constructed_type(name, signature).filter(|ty| types.contains(*ty)) {
+            Some(ty) => (3, ty.to_string(), true, name.to_string()),
+            None => (2, name.to_string(), false, String::new()),
+        },
+        Some(_) => (2, name.to_string(), false, String::new()),
+        None => (4, String::new(), false, String::new()),
+    }
+}
+
+/// The type a `New...` function constructs, going by its signature: the base
+/// name of its result `T` or `*T`, or of the first result in a list, as in
+/// `func NewHandler(cfg Config) (*Handler, error)`.
+fn constructed_type<'a>(name: &str, signature: Option<&'a str>) -> Option<&'a str> {
+    if !name.starts_with("New") {
+        return None;
+    }
+    let signature = signature?;
+    let params_start = signature.find('(')?;
+    let mut depth = 0usize;
+    let mut params_end = None;
+    for (offset, c) in signature[params_start..].char_indices() {
+        match c {
+            '(' | '[' => depth += 1,
+            ')' | ']' => {
+                depth = depth.saturating_sub(1);
+                if depth == 0 {
+                    params_end = Some(params_start + offset + 1);
+                    break;
+                }
+            }
+            _ => {}
+        }
+    }
+    let result = signature[params_end?..].trim();
+    let first = result
+        .strip_prefix('(')
+        .map_or(result, |list| list.split([',', ')']).next().unwrap_or(""))
+        .trim();
+    let base = first.strip_prefix('*').unwrap_or(first);
+    let base = base.split('[').next().unwrap_or(base).trim();
+    (!base.is_empty()).then_some(base)
+}
+
+/// [`format_file_outline`] with symbols and members ordered by `layout.sort`,
+/// and with `layout.notes` added.
+pub fn format_file_outline_sorted(
+    file: &str,
+    entry: &FileEntry,
+    reexports: &[OutlineReExport],
+    private_by_class: Option<&HashMap<String, Vec<PrivateMember>>>,
+    top_level_fns: Option<&[TopLevelFunction]>,
+    freshness: Option<&str>,
+    layout: &OutlineLayout,
+) -> String {
+    let &OutlineLayout { sort, ref notes } = layout;
     let mut lines = Vec::new();
     lines.push("---".to_string());
     lines.push(format!("file: {}", yaml_escape(file)));
@@ -36,6 +250,7 @@ pub fn format_file_outline(
         lines.push(format!("freshness: {}", yaml_escape(freshness)));
     }
     lines.push(format!("loc: {}", entry.loc));
+    push_notes(&mut lines, 0, &notes.file);
     push_inline_list(&mut lines, "imports", &entry.imports);
     push_inline_list(&mut lines, "dependencies", &entry.dependencies);
 
@@ -45,8 +260,28 @@ pub fn format_file_outline(
 
     if has_local_def {
         lines.push("symbols:".to_string());
-        push_indexed_symbols(&mut lines, entry, private_by_class, &reexport_names);
-        push_extra_top_level_symbols(&mut lines, entry, top_level_fns);
+        let mut symbols = indexed_symbols(entry, &reexport_names);
+        symbols.extend(extra_top_level_symbols(entry, top_level_fns));
+        sort_outline_symbols(&mut symbols, sort, |symbol| {
+            (
+                symbol.name,
+                symbol.metadata.declaration_kind.as_deref(),
+                symbol.metadata.signature.as_deref(),
+            )
+        });
+        for symbol in symbols {
+            push_symbol_entry(
+                &mut lines,
+                2,
+                symbol.name,
+                symbol.lines.as_ref(),
+                Some(&symbol.metadata),
+            );
+            push_notes(&mut lines, 4, notes.symbol(symbol.name));
+            if symbol.indexed {
+                push_members(&mut lines, entry, symbol.name, private_by_class, layout);
+            }
+        }
     }
 
     push_reexports(&mut lines, reexports);
@@ -76,46 +311,65 @@ fn has_extra_top_level_symbols(
         .unwrap_or(false)
 }
 
-fn push_indexed_symbols(
-    lines: &mut Vec<String>,
-    entry: &FileEntry,
-    private_by_class: Option<&HashMap<String, Vec<PrivateMember>>>,
+/// One top-level row of the `symbols:` block.
+struct OutlineSymbol<'a> {
+    name: &'a str,
+    lines: Option<ExportLines>,
+    metadata: SymbolMetadata,
+    /// Indexed symbols carry members; private top-level extras do not.
+    indexed: bool,
+}
+
+fn indexed_symbols<'a>(
+    entry: &'a FileEntry,
     reexport_names: &HashSet<&str>,
-) {
-    for (i, name) in entry.exports.iter().enumerate() {
-        if reexport_names.contains(name.as_str()) {
-            continue;
-        }
-        let symbol_lines = entry.export_lines.as_ref().and_then(|els| els.get(i));
-        let metadata = entry.export_metadata.get(name);
-        push_symbol_entry(lines, 2, name, symbol_lines, metadata);
-        push_members(lines, entry, name, private_by_class);
-    }
+) -> Vec<OutlineSymbol<'a>> {
+    entry
+        .exports
+        .iter()
+        .enumerate()
+        .filter(|(_, name)| !reexport_names.contains(name.as_str()))
+        .map(|(i, name)| OutlineSymbol {
+            name,
+            lines: entry
+                .export_lines
+                .as_ref()
+                .and_then(|els| els.get(i))
+                .cloned(),
+            metadata: entry.export_metadata.get(name).cloned().unwrap_or_default(),
+            indexed: true,
+        })
+        .collect()
 }
 
-fn push_extra_top_level_symbols(
-    lines: &mut Vec<String>,
+fn extra_top_level_symbols<'a>(
     entry: &FileEntry,
-    top_level_fns: Option<&[TopLevelFunction]>,
-) {
+    top_level_fns: Option<&'a [TopLevelFunction]>,
+) -> Vec<OutlineSymbol<'a>> {
     let Some(fns) = top_level_fns else {
-        return;
+        return Vec::new();
     };
-    for f in fns {
-        if entry.exports.iter().any(|name| name == &f.name) {
-            continue;
-        }
-        let line_range = ExportLines {
-            start: f.start,
-            end: f.end,
-        };
-        let metadata = SymbolMetadata {
-            signature: None,
-            visibility: Some("non_exported".to_string()),
-            declaration_kind: Some("fn".to_string()),
-        };
-        push_symbol_entry(lines, 2, &f.name, Some(&line_range), Some(&metadata));
-    }
+    fns.iter()
+        .filter(|f| !entry.exports.iter().any(|name| name == &f.name))
+        .map(|f| OutlineSymbol {
+            name: &f.name,
+            lines: Some(ExportLines {
+                start: f.start,
+                end: f.end,
+            }),
+            metadata: SymbolMetadata {
+                signature: f.metadata.signature.clone(),
+                visibility: Some("non_exported".to_string()),
+                declaration_kind: Some(
+                    f.metadata
+                        .declaration_kind
+                        .clone()
+                        .unwrap_or_else(|| "fn".to_string()),
+                ),
+            },
+            indexed: false,
+        })
+        .collect()
 }
 
 fn push_symbol_entry(
@@ -187,13 +441,22 @@ fn push_members(
     entry: &FileEntry,
     parent_name: &str,
     private_by_class: Option<&HashMap<String, Vec<PrivateMember>>>,
+    layout: &OutlineLayout,
 ) {
+    let sort = layout.sort;
     let mut members = collect_members(entry, parent_name, private_by_class);
     if members.is_empty() {
         return;
     }
 
     members.sort_by_key(|member| member.start);
+    sort_outline_symbols(&mut members, sort, |member| {
+        (
+            member.name.as_str(),
+            member.metadata.declaration_kind.as_deref(),
+            member.metadata.signature.as_deref(),
+        )
+    });
     lines.push(format!("{}members:", spaces(4)));
     for member in members {
         let line_range = ExportLines {
@@ -207,6 +470,8 @@ fn push_members(
             Some(&line_range),
             Some(&member.metadata),
         );
+        let dotted_name = format!("{parent_name}.{}", member.name);
+        push_notes(lines, 8, layout.notes.symbol(&dotted_name));
     }
 }
 
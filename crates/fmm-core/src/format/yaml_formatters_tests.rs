@@ -89,6 +89,135 @@ fn file_outline_members_sorted_by_source_position() {
     );
 }
 
+#[test]
+fn file_outline_sorted_by_name_orders_symbols_and_members() {
+    let entry = make_entry_with_methods(
+        vec![("Server", 1, 40), ("Config", 50, 60)],
+        vec![("Server.Stop", 10, 19), ("Server.Start", 20, 29)],
+    );
+    let out = format_file_outline_sorted(
+        "server.go",
+        &entry,
+        &[],
+        None,
+        None,
+        None,
+        &OutlineLayout {
+            sort: OutlineSort::Name,
+            ..Default::default()
+        },
+    );
+    assert!(out.find("  Config:").unwrap() < out.find("  Server:").unwrap());
+    assert!(out.find("- name: Start").unwrap() < out.find("- name: Stop").unwrap());
+}
+
+#[test]
+fn file_outline_notes_follow_loc_and_kind_rows() {
+    let entry = make_entry_with_methods(vec![("Server", 1, 40)], vec![("Server.Start", 20, 29)]);
+    let mut layout = OutlineLayout::default();
+    layout.notes.add_file(
+        "package_doc",
+        "Package server serves.\n\nIt listens: on :8080.",
+    );
+    layout
+        .notes
+        .add_symbol("Server", "tags", vec!["a".to_string(), "b:c".to_string()]);
+    layout
+        .notes
+        .add_symbol("Server.Start", "special", "entry point");
+    let out = format_file_outline_sorted("server.go", &entry, &[], None, None, None, &layout);
+    assert!(
+        out.contains(
+            "loc: 400\npackage_doc: |\n  Package server serves.\n\n  It listens: on :8080.\n"
+        ),
+        "{out}"
+    );
+    assert!(out.contains("    tags: [a, 'b:c']\n"), "{out}");
+    assert!(out.contains("        special: entry point"), "{out}");
+}
+
+#[test]
+fn sort_outline_symbols_by_kind_groups_then_names() {
+    let mut symbols = vec![
+        ("Process", Some("fn")),
+        ("MaxRetries", Some("const")),
+        ("unknown", None),
+        ("NewHandler", Some("fn")),
+        ("Config", Some("struct")),
+    ];
+    sort_outline_symbols(&mut symbols, OutlineSort::Kind, |&(name, kind)| {
+        (name, kind, None)
+    });
+    let names: Vec<&str> = symbols.iter().map(|(name, _)| *name).collect();
+    assert_eq!(
+        names,
+        vec!["MaxRetries", "NewHandler", "Process", "Config", "unknown"]
+    );
+}
+
+#[test]
+fn sort_outline_symbols_godoc_groups_constructors_under_types() {
+    let mut symbols = vec![
+        ("Status", Some("type"), Some("type Status int")),
+        ("MaxRetries", Some("const"), Some("const MaxRetries = 3")),
+        ("Handler", Some("struct"), Some("type Handler struct")),
+        (
+            "Process",
+            Some("fn"),
+            Some("func Process(w http.ResponseWriter)"),
+        ),
+        (
+            "ErrClosed",
+            Some("var"),
+            Some("var ErrClosed = errors.New(\"closed\")"),
+        ),
+        (
+            "NewHandler",
+            Some("fn"),
+            Some("func NewHandler(cfg Config) *Handler"),
+        ),
+        (
+            "StatusActive",
+            Some("const"),
+            Some("const StatusActive Status = iota"),
+        ),
+        ("Config", Some("struct"), Some("type Config struct")),
+        (
+            "NewServer",
+            Some("fn"),
+            Some("func NewServer() (*Server, error)"),
+        ),
+    ];
+    sort_outline_symbols(&mut symbols, OutlineSort::Godoc, |&(name, kind, sig)| {
+        (name, kind, sig)
+    });
+    let names: Vec<&str> = symbols.iter().map(|(name, _, _)| *name).collect();
+    // Server is declared elsewhere, so NewServer stays an ordinary function.
+    assert_eq!(
+        names,
+        vec![
+            "MaxRetries",
+            "StatusActive",
+            "ErrClosed",
+            "NewServer",
+            "Process",
+            "Config",
+            "Handler",
+            "NewHandler",
+            "Status",
+        ]
+    );
+}
+
+#[test]
+fn sort_outline_symbols_by_source_keeps_order() {
+    let mut symbols = vec![("b", None), ("a", None)];
+    sort_outline_symbols(&mut symbols, OutlineSort::Source, |&(name, kind)| {
+        (name, kind, None)
+    });
+    assert_eq!(symbols, vec![("b", None), ("a", None)]);
+}
+
 #[test]
 fn file_outline_no_methods_unchanged() {
     let entry = make_entry_with_methods(vec![("foo", 1, 10), ("bar", 12, 20)], vec![]);
@@ -354,6 +483,37 @@ fn file_outline_private_field_annotated_correctly_when_public_methods_present()
     assert!(!out.contains("# private field"));
 }
 
+#[test]
+fn file_outline_top_level_declarations_render_carried_kind() {
+    use crate::manifest::private_members::TopLevelFunction;
+    use crate::parser::DeclarationKind;
+
+    let entry = make_bare_entry();
+    let top_level = vec![
+        TopLevelFunction::new("privateState", 3, 5).with_metadata(SymbolMetadata::from_parts(
+            Some("type privateState struct".to_string()),
+            None,
+            Some(DeclarationKind::Struct),
+        )),
+        TopLevelFunction::new("helper", 7, 9),
+    ];
+
+    let out = format_file_outline("pkg/state.go", &entry, &[], None, Some(&top_level), None);
+
+    assert!(
+        out.contains("  privateState:\n    lines: [3, 5]\n    size: 3\n    signature: type privateState struct\n    visibility: non_exported\n    kind: struct"),
+        "unexported type should keep its kind; got:\n{}",
+        out
+    );
+    assert!(
+        out.contains(
+            "  helper:\n    lines: [7, 9]\n    size: 3\n    visibility: non_exported\n    kind: fn"
+        ),
+        "declaration without a kind should default to fn; got:\n{}",
+        out
+    );
+}
+
 #[test]
 fn file_outline_include_private_does_not_duplicate_indexed_private_members() {
     use crate::manifest::private_members::PrivateMember;
@@ -0,0 +1,196 @@
+//! Go private member and top-level declaration extraction.
+//!
+//! Go has no access modifiers: an identifier is exported when it starts with
+//! an uppercase letter. The index keeps only exported declarations, so the
+//! lowercase ones (`helperFunc`, `privateState`, `defaultPort`,
+//! `(h *Handler) validate`, the `state` field, an interface's `flush()`) are
+//! recovered here on demand.
+
+use super::{PrivateMember, PrivateMemberExtractor, TopLevelFunction};
+use crate::manifest::SymbolMetadata;
+use crate::parser::ExportEntry;
+use crate::parser::builtin::go::{
+    go_field_entry, go_method_entry, interface_methods, is_ignored, is_ignored_file,
+    receiver_type_name, struct_fields, unexported_declarations,
+};
+use anyhow::Result;
+use std::collections::HashMap;
+
+pub(super) struct GoPrivateMemberExtractor;
+
+impl PrivateMemberExtractor for GoPrivateMemberExtractor {
+    fn extensions(&self) -> &'static [&'static str] {
+        &["go"]
+    }
+
+    fn extract_top_level_functions(
+        &self,
+        source: &[u8],
+        exports: &[&str],
+    ) -> Result<Vec<TopLevelFunction>> {
+        Ok(extract_go_top_level(source, exports).unwrap_or_default())
+    }
+
+    fn extract_private_members(
+        &self,
+        source: &[u8],
+        class_names: &[&str],
+    ) -> Result<HashMap<String, Vec<PrivateMember>>> {
+        Ok(extract_go_private(source, class_names).unwrap_or_default())
+    }
+}
+
+// ---------------------------------------------------------------------------
+// Go top-level declaration extraction
+// ---------------------------------------------------------------------------
+
+/// Extract unexported top-level functions, types, consts and vars, each with
+/// its signature and declaration kind.
+///
+/// Methods are excluded: they belong to their receiver type and are served
+/// through [`extract_go_private`].
+fn extract_go_top_level(source: &[u8], exports: &[&str]) -> Option<Vec<TopLevelFunction>> {
+    let tree = parse_go(source)?;
+    let root = tree.root_node();
+    if is_ignored_file(root, source) {
+        return Some(Vec::new());
+    }
+
+    let result = unexported_declarations(root, source)
+        .into_iter()
+        .filter(|entry| !exports.contains(&entry.name.as_str()))
+        .map(|entry| {
+            let metadata = SymbolMetadata::from_parts(
+                entry.signature,
+                entry.visibility,
+                entry.declaration_kind,
+            );
+            TopLevelFunction::new(entry.name, entry.start_line, entry.end_line)
+                .with_metadata(metadata)
+        })
+        .collect();
+    Some(result)
+}
+
+// ---------------------------------------------------------------------------
+// Go private member extraction (unexported fields and methods)
+// ---------------------------------------------------------------------------
+
+fn extract_go_private(
+    source: &[u8],
+    class_names: &[&str],
+) -> Option<HashMap<String, Vec<PrivateMember>>> {
+    let tree = parse_go(source)?;
+    let mut result: HashMap<String, Vec<PrivateMember>> = HashMap::new();
+    let root = tree.root_node();
+    if is_ignored_file(root, source) {
+        return Some(result);
+    }
+
+    for i in 0..root.child_count() {
+        let child = match root.child(i as u32) {
+            Some(c) if !is_ignored(c, source) => c,
+            _ => continue,
+        };
+
+        match child.kind() {
+            "type_declaration" => {
+                for j in 0..child.child_count() {
+                    let Some(spec) = child
+                        .child(j as u32)
+                        .filter(|n| n.kind() == "type_spec" && !is_ignored(*n, source))
+                    else {
+                        continue;
+                    };
+                    let Some(type_name) =
+                        decl_name(spec, source).filter(|name| class_names.contains(&name.as_str()))
+                    else {
+                        continue;
+                    };
+
+                    let mut members = collect_unexported_fields(spec, source, &type_name);
+                    members.extend(collect_unexported_interface_methods(
+                        spec, source, &type_name,
+                    ));
+                    if !members.is_empty() {
+                        result.entry(type_name).or_default().extend(members);
+                    }
+                }
+            }
+            "method_declaration" => {
+                if let Some(type_name) = receiver_type_name(child, source)
+                    && class_names.contains(&type_name.as_str())
+                    && let Some(name) = decl_name(child, source)
+                    && !is_exported(&name)
+                {
+                    let entry = go_method_entry(name, child, source, type_name.clone());
+                    result
+                        .entry(type_name)
+                        .or_default()
+                        .push(private_member(entry, true));
+                }
+            }
+            _ => {}
+        }
+    }
+
+    for members in result.values_mut() {
+        members.sort_by_key(|m| m.start);
+    }
+
+    Some(result)
+}
+
+fn collect_unexported_fields(
+    spec: tree_sitter::Node,
+    source: &[u8],
+    type_name: &str,
+) -> Vec<PrivateMember> {
+    struct_fields(spec, source)
+        .into_iter()
+        .filter(|field| !is_exported(&field.name))
+        .map(|field| {
+            let entry = go_field_entry(field.name, field.node, source, type_name.to_string());
+            private_member(entry, false)
+        })
+        .collect()
+}
+
+fn collect_unexported_interface_methods(
+    spec: tree_sitter::Node,
+    source: &[u8],
+    type_name: &str,
+) -> Vec<PrivateMember> {
+    interface_methods(spec, source)
+        .into_iter()
+        .filter(|method| !is_exported(&method.name))
+        .map(|method| {
+            let entry = go_method_entry(method.name, method.node, source, type_name.to_string());
+            private_member(entry, true)
+        })
+        .collect()
+}
+
+fn private_member(entry: ExportEntry, is_method: bool) -> PrivateMember {
+    let metadata =
+        SymbolMetadata::from_parts(entry.signature, entry.visibility, entry.declaration_kind);
+    PrivateMember::new(entry.name, entry.start_line, entry.end_line, is_method)
+        .with_metadata(metadata)
+}
+
+fn parse_go(source: &[u8]) -> Option<tree_sitter::Tree> {
+    let lang: tree_sitter::Language = tree_sitter_go::LANGUAGE.into();
+    let mut parser = tree_sitter::Parser::new();
+    parser.set_language(&lang).ok()?;
+    parser.parse(source, None)
+}
+
+fn decl_name(node: tree_sitter::Node, source: &[u8]) -> Option<String> {
+    node.child_by_field_name("name")
+        .and_then(|n| n.utf8_text(source).ok())
+        .map(|s| s.to_string())
+}
+
+fn is_exported(name: &str) -> bool {
+    name.starts_with(|c: char| c.is_uppercase())
+}
@@ -8,6 +8,7 @@
 //! Language support is extensible: add a new `PrivateMemberExtractor` impl
 //! in a per-language file and register it in `extractors()`.
 
+mod go;
 mod python;
 mod rust;
 mod typescript;
@@ -21,7 +22,8 @@ use std::path::Path;
 
 use super::SymbolMetadata;
 
-/// A non-exported top-level declaration (function, arrow function, or class).
+/// A non-exported top-level declaration (function, arrow function, or class;
+/// for Go also types, consts and vars).
 ///
 /// Extracted on demand when `include_private: true` is requested.
 /// Also used by `fmm_read_symbol` for the `file:symbol` notation.
@@ -33,6 +35,25 @@ pub struct TopLevelFunction {
     pub start: usize,
     /// 1-based end line.
     pub end: usize,
+    /// Optional parser metadata. Renderers treat a declaration without a
+    /// recorded kind as a function.
+    pub metadata: SymbolMetadata,
+}
+
+impl TopLevelFunction {
+    pub fn new(name: impl Into<String>, start: usize, end: usize) -> Self {
+        Self {
+            name: name.into(),
+            start,
+            end,
+            metadata: SymbolMetadata::default(),
+        }
+    }
+
+    pub fn with_metadata(mut self, metadata: SymbolMetadata) -> Self {
+        self.metadata = metadata;
+        self
+    }
 }
 
 /// A private class member (method or field) extracted on demand.
@@ -103,6 +124,7 @@ fn extractors() -> Vec<Box<dyn PrivateMemberExtractor>> {
         Box::new(typescript::TsPrivateMemberExtractor),
         Box::new(python::PyPrivateMemberExtractor),
         Box::new(rust::RsPrivateMemberExtractor),
+        Box::new(go::GoPrivateMemberExtractor),
     ]
 }
 
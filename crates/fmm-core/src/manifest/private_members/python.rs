@@ -52,11 +52,11 @@ fn extract_py_top_level(source: &[u8], exports: &[&str]) -> Option<Vec<TopLevelF
             && let Ok(name) = name_node.utf8_text(source)
             && !exports.contains(&name)
         {
-            result.push(TopLevelFunction {
-                name: name.to_string(),
-                start: child.start_position().row + 1,
-                end: child.end_position().row + 1,
-            });
+            result.push(TopLevelFunction::new(
+                name.to_string(),
+                child.start_position().row + 1,
+                child.end_position().row + 1,
+            ));
         }
     }
 
@@ -59,11 +59,11 @@ fn extract_rs_top_level(source: &[u8], exports: &[&str]) -> Option<Vec<TopLevelF
             && let Some(name) = fn_name(child, source)
             && !exports.contains(&name.as_str())
         {
-            result.push(TopLevelFunction {
+            result.push(TopLevelFunction::new(
                 name,
-                start: child.start_position().row + 1,
-                end: child.end_position().row + 1,
-            });
+                child.start_position().row + 1,
+                child.end_position().row + 1,
+            ));
         }
     }
 
@@ -557,3 +557,251 @@ impl<T> Container<T> {
         "pub method should not appear: {names:?}"
     );
 }
+
+// ---------------------------------------------------------------------------
+// Go extraction tests
+// ---------------------------------------------------------------------------
+
+#[test]
+fn go_unexported_struct_fields_extracted() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = r#"package server
+
+type Handler struct {
+	Name   string
+	state  privateState
+	client *redis.Client
+}
+"#;
+    std::fs::write(tmp.path().join("handler.go"), src).unwrap();
+
+    let result = extract_private_members(tmp.path(), "handler.go", &["Handler"]);
+    let members = result.get("Handler").expect("Handler should have members");
+    let names: Vec<&str> = members.iter().map(|m| m.name.as_str()).collect();
+    assert_eq!(names, vec!["state", "client"]);
+    assert!(members.iter().all(|m| !m.is_method));
+    assert_eq!(
+        members[1].metadata.signature.as_deref(),
+        Some("client *redis.Client")
+    );
+    assert_eq!(
+        members[1].metadata.visibility.as_deref(),
+        Some("non_exported")
+    );
+}
+
+#[test]
+fn go_unknown_struct_returns_empty() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = "package server\n\ntype Handler struct {\n\tstate int\n}\n";
+    std::fs::write(tmp.path().join("handler.go"), src).unwrap();
+
+    let result = extract_private_members(tmp.path(), "handler.go", &["Other"]);
+    assert!(result.is_empty());
+}
+
+#[test]
+fn go_top_level_unexported_declarations_extracted() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = r#"package server
+
+type privateState struct {
+	counter int
+}
+
+type Handler struct{}
+
+func NewHandler() *Handler { return &Handler{} }
+
+func (h *Handler) validate() error { return nil }
+
+func helperFunc() string {
+	return "internal"
+}
+"#;
+    std::fs::write(tmp.path().join("handler.go"), src).unwrap();
+
+    let result = extract_top_level_functions(tmp.path(), "handler.go", &["Handler", "NewHandler"]);
+    let names: Vec<&str> = result.iter().map(|f| f.name.as_str()).collect();
+    assert_eq!(names, vec!["privateState", "helperFunc"]);
+    assert_eq!(
+        find_top_level_function_range(tmp.path(), "handler.go", "helperFunc"),
+        Some((13, 15))
+    );
+}
+
+#[test]
+fn go_top_level_unexported_declarations_carry_kind_and_signature() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = r#"package server
+
+const defaultPort = 8080
+
+const (
+	modeRead = iota
+	modeWrite
+)
+
+var registry = map[string]int{}
+
+type privateState struct {
+	counter int
+}
+
+type handlerFunc func() error
+
+func helperFunc() string {
+	return "internal"
+}
+"#;
+    std::fs::write(tmp.path().join("server.go"), src).unwrap();
+
+    let result = extract_top_level_functions(tmp.path(), "server.go", &[]);
+    let summary: Vec<(&str, Option<&str>, Option<&str>)> = result
+        .iter()
+        .map(|f| {
+            (
+                f.name.as_str(),
+                f.metadata.declaration_kind.as_deref(),
+                f.metadata.signature.as_deref(),
+            )
+        })
+        .collect();
+    assert_eq!(
+        summary,
+        vec![
+            (
+                "defaultPort",
+                Some("const"),
+                Some("const defaultPort = 8080")
+            ),
+            ("modeRead", Some("const"), Some("const modeRead = iota")),
+            ("modeWrite", Some("const"), Some("const modeWrite = iota")),
+            (
+                "registry",
+                Some("var"),
+                Some("var registry = map[string]int{}")
+            ),
+            (
+                "privateState",
+                Some("struct"),
+                Some("type privateState struct")
+            ),
+            (
+                "handlerFunc",
+                Some("type"),
+                Some("type handlerFunc func() error")
+            ),
+            ("helperFunc", Some("fn"), Some("func helperFunc() string")),
+        ]
+    );
+    assert!(
+        result
+            .iter()
+            .all(|f| f.metadata.visibility.as_deref() == Some("non_exported"))
+    );
+}
+
+#[test]
+fn go_fmm_ignore_hides_private_declarations() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = r#"package server
+
+type Handler struct {
+	name string
+	//fmm:ignore
+	token string
+}
+
+//fmm:ignore
+func (h *Handler) reset() {}
+
+func (h *Handler) validate() error { return nil }
+
+//fmm:ignore
+func helperFunc() {}
+
+func otherHelper() {}
+"#;
+    std::fs::write(tmp.path().join("handler.go"), src).unwrap();
+
+    let top_level = extract_top_level_functions(tmp.path(), "handler.go", &["Handler"]);
+    let names: Vec<&str> = top_level.iter().map(|f| f.name.as_str()).collect();
+    assert_eq!(names, vec!["otherHelper"]);
+
+    let members = extract_private_members(tmp.path(), "handler.go", &["Handler"]);
+    let names: Vec<&str> = members["Handler"].iter().map(|m| m.name.as_str()).collect();
+    assert_eq!(names, vec!["name", "validate"]);
+}
+
+#[test]
+fn go_fmm_ignore_file_hides_private_declarations() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = "//fmm:ignore-file\n\npackage server\n\nfunc helperFunc() {}\n";
+    std::fs::write(tmp.path().join("gen.go"), src).unwrap();
+
+    assert!(extract_top_level_functions(tmp.path(), "gen.go", &[]).is_empty());
+}
+
+#[test]
+fn go_unexported_methods_extracted_under_receiver() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = r#"package server
+
+type Handler struct {
+	config Config
+}
+
+func (h *Handler) Serve() {}
+
+func (h *Handler) validate() error {
+	return nil
+}
+
+func (o Other) reset() {}
+"#;
+    std::fs::write(tmp.path().join("handler.go"), src).unwrap();
+
+    let result = extract_private_members(tmp.path(), "handler.go", &["Handler"]);
+    let members = result.get("Handler").expect("Handler should have members");
+    let names: Vec<&str> = members.iter().map(|m| m.name.as_str()).collect();
+    assert_eq!(names, vec!["config", "validate"]);
+
+    let validate = &members[1];
+    assert!(validate.is_method);
+    assert_eq!(
+        validate.metadata.signature.as_deref(),
+        Some("func (h *Handler) validate() error")
+    );
+    assert_eq!(
+        validate.metadata.declaration_kind.as_deref(),
+        Some("method")
+    );
+    assert_eq!(
+        find_private_method_range(tmp.path(), "handler.go", "Handler", "validate"),
+        Some((9, 11))
+    );
+}
+
+#[test]
+fn go_unexported_interface_methods_extracted() {
+    let tmp = tempfile::TempDir::new().unwrap();
+    let src = r#"package storage
+
+type Store interface {
+	Get(key string) (string, error)
+	flush() error
+}
+"#;
+    std::fs::write(tmp.path().join("store.go"), src).unwrap();
+
+    let result = extract_private_members(tmp.path(), "store.go", &["Store"]);
+    let members = result.get("Store").expect("Store should have members");
+    let names: Vec<&str> = members.iter().map(|m| m.name.as_str()).collect();
+    assert_eq!(names, vec!["flush"]);
+    assert!(members[0].is_method);
+    assert_eq!(
+        members[0].metadata.signature.as_deref(),
+        Some("flush() error")
+    );
+}
@@ -1,4 +1,4 @@
-use super::query_helpers::{collect_matches, compile_query, make_parser};
+use super::query_helpers::{collect_matches, compile_query, first_syntax_error, make_parser};
 use crate::parser::{ExportEntry, Metadata, ParseResult, Parser};
 use anyhow::Result;
 use std::collections::{HashMap, HashSet};
@@ -216,6 +216,10 @@ impl Parser for CppParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "cpp"
     }
@@ -5,7 +5,7 @@ use streaming_iterator::StreamingIterator;
 use tree_sitter::{Language, Parser as TSParser, Query, QueryCursor};
 
 use super::query_helpers::{
-    collect_matches, compile_query, has_modifier, make_parser, push_export,
+    collect_matches, compile_query, first_syntax_error, has_modifier, make_parser, push_export,
 };
 
 pub struct CSharpParser {
@@ -213,6 +213,10 @@ impl Parser for CSharpParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "csharp"
     }
@@ -3,7 +3,7 @@ use anyhow::Result;
 use std::collections::{HashMap, HashSet};
 use tree_sitter::{Language, Parser as TSParser};
 
-use super::query_helpers::{extract_child_text, make_parser};
+use super::query_helpers::{extract_child_text, first_syntax_error, make_parser};
 
 pub struct DartParser {
     parser: TSParser,
@@ -285,6 +285,10 @@ impl Parser for DartParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "dart"
     }
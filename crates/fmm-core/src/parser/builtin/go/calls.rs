@@ -0,0 +1,151 @@
+use super::receivers::{declaration_key, receiver_type_name};
+use serde_json::Value;
+use std::collections::{BTreeSet, HashSet};
+use tree_sitter::Node;
+
+/// Calls from each function and method to functions and methods declared in
+/// this file, keyed and valued as `name` / `Receiver.name`. Only calls that
+/// resolve statically are recorded: a bare `helper()` naming a top-level
+/// function, or `h.validate()` through the method's own receiver. Calls
+/// through interface values, func variables or anything else defined outside
+/// the file are left out rather than guessed. Recursion is a self edge, and
+/// calls inside closures belong to the enclosing declaration.
+pub(super) fn calls(root_node: Node, source_bytes: &[u8]) -> serde_json::Map<String, Value> {
+    let mut functions = HashSet::new();
+    let mut methods = HashSet::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        let Some(key) = declaration_key(decl, source_bytes) else {
+            continue;
+        };
+        if decl.kind() == "function_declaration" {
+            functions.insert(key);
+        } else {
+            methods.insert(key);
+        }
+    }
+
+    let mut graph = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        let (Some(key), Some(body)) = (
+            declaration_key(decl, source_bytes),
+            decl.child_by_field_name("body"),
+        ) else {
+            continue;
+        };
+
+        let mut bound = HashSet::new();
+        for field in ["parameters", "result"] {
+            if let Some(params) = decl.child_by_field_name(field) {
+                collect_bindings(params, source_bytes, &mut bound);
+            }
+        }
+        collect_bindings(body, source_bytes, &mut bound);
+        let receiver =
+            receiver_binding(decl, source_bytes).filter(|(name, _)| !bound.contains(name));
+
+        let mut callees = BTreeSet::new();
+        let mut call_nodes = Vec::new();
+        collect_calls(body, &mut call_nodes);
+        for call in call_nodes {
+            let Some(function) = call.child_by_field_name("function") else {
+                continue;
+            };
+            let callee = match function.kind() {
+                "identifier" => function
+                    .utf8_text(source_bytes)
+                    .ok()
+                    .filter(|name| !bound.contains(*name) && functions.contains(*name))
+                    .map(str::to_string),
+                "selector_expression" => receiver.as_ref().and_then(|(var, ty)| {
+                    let operand = function.child_by_field_name("operand")?;
+                    let field = function.child_by_field_name("field")?;
+                    if operand.kind() != "identifier"
+                        || operand.utf8_text(source_bytes).ok()? != var.as_str()
+                    {
+                        return None;
+                    }
+                    let method = format!("{ty}.{}", field.utf8_text(source_bytes).ok()?);
+                    methods.contains(&method).then_some(method)
+                }),
+                _ => None,
+            };
+            if let Some(callee) = callee {
+                callees.insert(callee);
+            }
+        }
+
+        if !callees.is_empty() {
+            graph.insert(
+                key,
+                Value::Array(callees.into_iter().map(Value::String).collect()),
+            );
+        }
+    }
+    graph
+}
+
+/// The receiver variable of a method and its base type: `("h", "Handler")`
+/// for `(h *Handler)`. None for functions and unnamed receivers.
+fn receiver_binding(decl: Node, source_bytes: &[u8]) -> Option<(String, String)> {
+    let receiver = decl.child_by_field_name("receiver")?;
+    let mut cursor = receiver.walk();
+    let param = receiver
+        .children(&mut cursor)
+        .find(|child| child.kind() == "parameter_declaration")?;
+    let name = param
+        .child_by_field_name("name")?
+        .utf8_text(source_bytes)
+        .ok()?;
+    if name == "_" {
+        return None;
+    }
+    Some((name.to_string(), receiver_type_name(decl, source_bytes)?))
+}
+
+/// Every name bound by parameters or local declarations under `node`. Scope
+/// is ignored: a name bound anywhere in a function shadows a package-level
+/// declaration for the whole function, which can only drop edges.
+fn collect_bindings(node: Node, source_bytes: &[u8], bound: &mut HashSet<String>) {
+    let targets: Vec<Node> = match node.kind() {
+        "parameter_declaration" | "variadic_parameter_declaration" | "var_spec" | "const_spec" => {
+            let mut cursor = node.walk();
+            node.children_by_field_name("name", &mut cursor).collect()
+        }
+        "short_var_declaration" | "range_clause" | "receive_statement" => {
+            node.child_by_field_name("left").into_iter().collect()
+        }
+        "type_switch_statement" => node.child_by_field_name("alias").into_iter().collect(),
+        _ => Vec::new(),
+    };
+    for target in targets {
+        collect_identifiers(target, source_bytes, bound);
+    }
+
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        collect_bindings(child, source_bytes, bound);
+    }
+}
+
+fn collect_identifiers(node: Node, source_bytes: &[u8], bound: &mut HashSet<String>) {
+    if node.kind() == "identifier" {
+        bound.extend(node.utf8_text(source_bytes).ok().map(str::to_string));
+        return;
+    }
+    let mut cursor = node.walk();
+    for child in node.named_children(&mut cursor) {
+        collect_identifiers(child, source_bytes, bound);
+    }
+}
+
+fn collect_calls<'tree>(node: Node<'tree>, calls: &mut Vec<Node<'tree>>) {
+    if node.kind() == "call_expression" {
+        calls.push(node);
+    }
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        collect_calls(child, calls);
+    }
+}
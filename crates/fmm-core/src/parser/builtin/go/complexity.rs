@@ -0,0 +1,79 @@
+use super::receivers::declaration_key;
+use serde_json::{Value, json};
+use tree_sitter::Node;
+
+/// McCabe cyclomatic complexity of every function and method: one plus each
+/// decision point in its body. Closures are scored on their own under the
+/// names the Go runtime gives them (`Process.func1`, `Process.func1.1`) and
+/// do not add to the enclosing function.
+pub(super) fn complexity(root_node: Node, source_bytes: &[u8]) -> serde_json::Map<String, Value> {
+    let mut scores = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        let Some(key) = declaration_key(decl, source_bytes) else {
+            continue;
+        };
+        if let Some(body) = decl.child_by_field_name("body") {
+            score_function(&key, body, false, source_bytes, &mut scores);
+        }
+    }
+    scores
+}
+
+/// Score one function body under `key`, then each closure inside it.
+/// Closures of a declared function are `key.funcN`; closures nested in a
+/// closure append a bare `.N`, as in runtime stack traces.
+fn score_function(
+    key: &str,
+    body: Node,
+    is_closure: bool,
+    source_bytes: &[u8],
+    scores: &mut serde_json::Map<String, Value>,
+) {
+    let mut closures = Vec::new();
+    let decisions = decision_points(body, source_bytes, &mut closures);
+    scores.insert(key.to_string(), json!(decisions + 1));
+
+    for (index, closure) in closures.into_iter().enumerate() {
+        let closure_key = if is_closure {
+            format!("{key}.{}", index + 1)
+        } else {
+            format!("{key}.func{}", index + 1)
+        };
+        if let Some(closure_body) = closure.child_by_field_name("body") {
+            score_function(&closure_key, closure_body, true, source_bytes, scores);
+        }
+    }
+}
+
+/// Decision points under `node`, stopping at function literals, which are
+/// collected into `closures` in source order.
+fn decision_points<'tree>(
+    node: Node<'tree>,
+    source_bytes: &[u8],
+    closures: &mut Vec<Node<'tree>>,
+) -> usize {
+    let mut count = 0;
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        match child.kind() {
+            "func_literal" => {
+                closures.push(child);
+                continue;
+            }
+            "if_statement" | "for_statement" | "expression_case" | "type_case"
+            | "communication_case" => count += 1,
+            "binary_expression" => {
+                let operator = child
+                    .child_by_field_name("operator")
+                    .and_then(|op| op.utf8_text(source_bytes).ok());
+                if matches!(operator, Some("&&" | "||")) {
+                    count += 1;
+                }
+            }
+            _ => {}
+        }
+        count += decision_points(child, source_bytes, closures);
+    }
+    count
+}
@@ -0,0 +1,236 @@
+use super::GoParser;
+use super::directives::is_ignored;
+use serde_json::Value;
+use std::collections::HashMap;
+use tree_sitter::Node;
+
+/// An untyped constant the folder can represent exactly.
+#[derive(Clone)]
+enum Constant {
+    Int(i128),
+    Str(String),
+}
+
+impl Constant {
+    /// Go literal spelling: `1024`, `"ab"`.
+    fn literal(&self) -> String {
+        match self {
+            Constant::Int(n) => n.to_string(),
+            Constant::Str(s) => go_quote(s),
+        }
+    }
+}
+
+/// `s` as Go's `strconv.Quote` spells it: printable characters as they are,
+/// the single-letter escapes Go has (`\n`, `\t`, ...), and `\xNN`, `\uNNNN`
+/// or `\UNNNNNNNN` for any other control or non-printable character.
+fn go_quote(s: &str) -> String {
+    let mut quoted = String::with_capacity(s.len() + 2);
+    quoted.push('"');
+    for c in s.chars() {
+        match c {
+            '\x07' => quoted.push_str("\\a"),
+            '\x08' => quoted.push_str("\\b"),
+            '\x0c' => quoted.push_str("\\f"),
+            '\n' => quoted.push_str("\\n"),
+            '\r' => quoted.push_str("\\r"),
+            '\t' => quoted.push_str("\\t"),
+            '\x0b' => quoted.push_str("\\v"),
+            '\\' | '"' => {
+                quoted.push('\\');
+                quoted.push(c);
+            }
+            c if is_go_printable(c) => quoted.push(c),
+            c if c.is_ascii() => quoted.push_str(&format!("\\x{:02x}", c as u32)),
+            c if (c as u32) < 0x10000 => quoted.push_str(&format!("\\u{:04x}", c as u32)),
+            c => quoted.push_str(&format!("\\U{:08x}", c as u32)),
+        }
+    }
+    quoted.push('"');
+    quoted
+}
+
+/// Close to Go's `unicode.IsPrint`: control characters, spaces other than
+/// U+0020, the common format characters (soft hyphen, zero-width and
+/// bidirectional marks, BOM) and private use characters are not printable.
+/// Without a Unicode table at hand, unassigned code points count as printable.
+fn is_go_printable(c: char) -> bool {
+    if c == ' ' {
+        return true;
+    }
+    !(c.is_control()
+        || c.is_whitespace()
+        || matches!(
+            c,
+            '\u{00ad}'
+                | '\u{200b}'..='\u{200f}'
+                | '\u{202a}'..='\u{202e}'
+                | '\u{2060}'..='\u{206f}'
+                | '\u{feff}'
+                | '\u{e000}'..='\u{f8ff}'
+                | '\u{f0000}'..='\u{10ffff}'
+        ))
+}
+
+/// Value of every exported constant, keyed by name. Integer and string
+/// expressions over literals, `iota` and earlier constants of the file are
+/// folded (`1 << 10` is `1024`, `"a" + "b"` is `"ab"`); any other value is
+/// the expression as written. Implicit specs repeat the previous expression
+/// with their own `iota`, as the compiler does.
+pub(super) fn const_values(root_node: Node, source_bytes: &[u8]) -> serde_json::Map<String, Value> {
+    let mut known = HashMap::new();
+    let mut values = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "const_declaration" || is_ignored(decl, source_bytes) {
+            continue;
+        }
+        let mut inherited: Option<Node> = None;
+        let mut spec_cursor = decl.walk();
+        let specs = decl
+            .children(&mut spec_cursor)
+            .filter(|child| child.kind() == "const_spec");
+        for (iota, spec) in specs.enumerate() {
+            if let Some(value) = spec.child_by_field_name("value") {
+                inherited = Some(value);
+            }
+            let Some(expressions) = inherited else {
+                continue;
+            };
+            let mut expr_cursor = expressions.walk();
+            let expressions: Vec<Node> = expressions
+                .named_children(&mut expr_cursor)
+                .filter(|expr| expr.kind() != "comment")
+                .collect();
+
+            let mut name_cursor = spec.walk();
+            let names: Vec<&str> = spec
+                .children_by_field_name("name", &mut name_cursor)
+                .filter_map(|name| name.utf8_text(source_bytes).ok())
+                .collect();
+            let ignored = is_ignored(spec, source_bytes);
+            for (name, expr) in names.into_iter().zip(expressions) {
+                let folded = fold(expr, iota as i128, &known, source_bytes);
+                if let Some(constant) = &folded {
+                    known.insert(name.to_string(), constant.clone());
+                }
+                if ignored || !GoParser::is_exported(name) {
+                    continue;
+                }
+                let value = match folded {
+                    Some(constant) => constant.literal(),
+                    None => expr.utf8_text(source_bytes).unwrap_or_default().to_string(),
+                };
+                values.insert(name.to_string(), Value::String(value));
+            }
+        }
+    }
+    values
+}
+
+fn fold(
+    expr: Node,
+    iota: i128,
+    known: &HashMap<String, Constant>,
+    source_bytes: &[u8],
+) -> Option<Constant> {
+    let text = expr.utf8_text(source_bytes).ok()?;
+    match expr.kind() {
+        "int_literal" => parse_int(text).map(Constant::Int),
+        "interpreted_string_literal" => {
+            let body = text.strip_prefix('"')?.strip_suffix('"')?;
+            // Escapes are left to the raw fallback rather than half-decoded.
+            (!body.contains('\\')).then(|| Constant::Str(body.to_string()))
+        }
+        "raw_string_literal" => {
+            let body = text.strip_prefix('`')?.strip_suffix('`')?;
+            Some(Constant::Str(body.replace('\r', "")))
+        }
+        "iota" => Some(Constant::Int(iota)),
+        "identifier" if text == "iota" => Some(Constant::Int(iota)),
+        "identifier" => known.get(text).cloned(),
+        "parenthesized_expression" => fold(expr.named_child(0)?, iota, known, source_bytes),
+        "unary_expression" => {
+            let operator = expr
+                .child_by_field_name("operator")?
+                .utf8_text(source_bytes)
+                .ok()?;
+            let Constant::Int(n) = fold(
+                expr.child_by_field_name("operand")?,
+                iota,
+                known,
+                source_bytes,
+            )?
+            else {
+                return None;
+            };
+            match operator {
+                "+" => Some(Constant::Int(n)),
+                "-" => n.checked_neg().map(Constant::Int),
+                "^" => Some(Constant::Int(!n)),
+                _ => None,
+            }
+        }
+        "binary_expression" => {
+            let operator = expr
+                .child_by_field_name("operator")?
+                .utf8_text(source_bytes)
+                .ok()?;
+            let left = fold(expr.child_by_field_name("left")?, iota, known, source_bytes)?;
+            let right = fold(
+                expr.child_by_field_name("right")?,
+                iota,
+                known,
+                source_bytes,
+            )?;
+            match (left, right) {
+                (Constant::Int(a), Constant::Int(b)) => fold_int(operator, a, b).map(Constant::Int),
+                (Constant::Str(a), Constant::Str(b)) if operator == "+" => {
+                    Some(Constant::Str(a + &b))
+                }
+                _ => None,
+            }
+        }
+        _ => None,
+    }
+}
+
+fn fold_int(operator: &str, a: i128, b: i128) -> Option<i128> {
+    match operator {
+        "+" => a.checked_add(b),
+        "-" => a.checked_sub(b),
+        "*" => a.checked_mul(b),
+        "/" => a.checked_div(b),
+        "%" => a.checked_rem(b),
+        "&" => Some(a & b),
+        "|" => Some(a | b),
+        "^" => Some(a ^ b),
+        "&^" => Some(a & !b),
+        "<<" => {
+            let shift = u32::try_from(b).ok()?;
+            let shifted = a.checked_shl(shift)?;
+            // checked_shl only rejects oversized shifts; bits shifted out are lost.
+            (shifted >> shift == a).then_some(shifted)
+        }
+        ">>" => Some(a >> u32::try_from(b).ok()?.min(127)),
+        _ => None,
+    }
+}
+
+/// Decimal, `0x`, `0o`, `0b` and legacy `0`-prefixed octal, with `_` separators.
+fn parse_int(text: &str) -> Option<i128> {
+    let digits = text.replace('_', "");
+    let lower = digits.to_ascii_lowercase();
+    let (radix, body) = if let Some(hex) = lower.strip_prefix("0x") {
+        (16, hex)
+    } else if let Some(octal) = lower.strip_prefix("0o") {
+        (8, octal)
+    } else if let Some(binary) = lower.strip_prefix("0b") {
+        (2, binary)
+    } else if lower.len() > 1 && lower.starts_with('0') {
+        (8, &lower[1..])
+    } else {
+        (10, lower.as_str())
+    };
+    i128::from_str_radix(body, radix).ok()
+}
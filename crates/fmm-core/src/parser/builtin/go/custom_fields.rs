@@ -0,0 +1,449 @@
+use super::GoParser;
+use super::calls::calls;
+use super::complexity::complexity;
+use super::const_values::const_values;
+use super::deprecated::deprecated;
+use super::directives::is_ignored;
+use super::error_returns::error_returns;
+use super::extract_exports::{declaration_specs, declared_type_names, spec_names};
+use super::import_usage::{import_kinds, unused_imports};
+use super::interface_elems::embedded_interfaces;
+use super::layout::struct_layouts;
+use super::markers::markers;
+use super::receivers::{declaration_key, inconsistent_receivers, receiver_type_name};
+use super::shadowing::shadowed;
+use super::special_functions::special_functions;
+use super::struct_fields::{embedded_field_text, struct_fields};
+use super::test_functions::test_kind;
+use super::undocumented::undocumented;
+use crate::parser::builtin::query_helpers::extract_field_text;
+use serde_json::{Value, json};
+use std::collections::HashMap;
+use tree_sitter::Node;
+
+impl GoParser {
+    pub(super) fn extract_custom_fields(
+        &self,
+        root_node: Node,
+        source_bytes: &[u8],
+    ) -> Option<HashMap<String, Value>> {
+        let mut fields = HashMap::new();
+
+        if let Some(constraint) = build_constraint(root_node, source_bytes) {
+            fields.insert("build_constraint".to_string(), Value::String(constraint));
+        }
+
+        let package = package_name(root_node, source_bytes);
+        if let Some(package) = &package {
+            fields.insert("package".to_string(), Value::String(package.clone()));
+        }
+
+        if let Some(import_path) = &self.import_path {
+            fields.insert(
+                "import_path".to_string(),
+                Value::String(import_path.clone()),
+            );
+        }
+
+        if let Some(package_doc) = package_doc(root_node, source_bytes) {
+            fields.insert("package_doc".to_string(), Value::String(package_doc));
+        }
+
+        let (import_aliases, dot_imports) = import_selectors(root_node, source_bytes);
+        if !import_aliases.is_empty() {
+            fields.insert("import_aliases".to_string(), Value::Object(import_aliases));
+        }
+        if !dot_imports.is_empty() {
+            fields.insert("dot_imports".to_string(), Value::Array(dot_imports));
+        }
+
+        let import_kinds = import_kinds(root_node, source_bytes, self.module_name.as_deref());
+        if !import_kinds.is_empty() {
+            fields.insert("import_kinds".to_string(), Value::Object(import_kinds));
+        }
+        let unused_imports = unused_imports(root_node, source_bytes);
+        if !unused_imports.is_empty() {
+            fields.insert("unused_imports".to_string(), Value::Array(unused_imports));
+        }
+
+        let const_blocks = const_blocks(root_node, source_bytes);
+        if !const_blocks.is_empty() {
+            fields.insert("const_blocks".to_string(), Value::Array(const_blocks));
+        }
+
+        let const_values = const_values(root_node, source_bytes);
+        if !const_values.is_empty() {
+            fields.insert("const_values".to_string(), Value::Object(const_values));
+        }
+
+        let embedded_fields = embedded_fields(root_node, source_bytes);
+        if !embedded_fields.is_empty() {
+            fields.insert(
+                "embedded_fields".to_string(),
+                Value::Object(embedded_fields),
+            );
+        }
+
+        let struct_layouts = struct_layouts(root_node, source_bytes);
+        if !struct_layouts.is_empty() {
+            fields.insert("struct_layout".to_string(), Value::Object(struct_layouts));
+        }
+
+        let body_lines = body_lines(root_node, source_bytes);
+        if !body_lines.is_empty() {
+            fields.insert("body_lines".to_string(), Value::Object(body_lines));
+        }
+
+        let complexity = complexity(root_node, source_bytes);
+        if !complexity.is_empty() {
+            fields.insert("complexity".to_string(), Value::Object(complexity));
+        }
+
+        let calls = calls(root_node, source_bytes);
+        if !calls.is_empty() {
+            fields.insert("calls".to_string(), Value::Object(calls));
+        }
+
+        let shadowed = shadowed(root_node, source_bytes);
+        if !shadowed.is_empty() {
+            fields.insert("shadowed".to_string(), Value::Array(shadowed));
+        }
+
+        let error_returns = error_returns(root_node, source_bytes);
+        if !error_returns.is_empty() {
+            fields.insert("error_returns".to_string(), Value::Object(error_returns));
+        }
+
+        let embedded_interfaces = embedded_interfaces_by_type(root_node, source_bytes);
+        if !embedded_interfaces.is_empty() {
+            fields.insert(
+                "embedded_interfaces".to_string(),
+                Value::Object(embedded_interfaces),
+            );
+        }
+
+        let markers = markers(root_node, source_bytes);
+        if !markers.is_empty() {
+            fields.insert("markers".to_string(), Value::Array(markers));
+        }
+
+        let undocumented = undocumented(root_node, source_bytes);
+        if !undocumented.is_empty() {
+            fields.insert("undocumented".to_string(), Value::Array(undocumented));
+        }
+
+        let test_kinds = test_kinds(root_node, source_bytes);
+        if !test_kinds.is_empty() {
+            fields.insert("test_kind".to_string(), Value::Object(test_kinds));
+        }
+
+        let special_functions = special_functions(root_node, source_bytes, package.as_deref());
+        if !special_functions.is_empty() {
+            fields.insert(
+                "special_functions".to_string(),
+                Value::Object(special_functions),
+            );
+        }
+
+        let deprecated = deprecated(root_node, source_bytes);
+        if !deprecated.is_empty() {
+            fields.insert("deprecated".to_string(), Value::Object(deprecated));
+        }
+
+        let external_receivers = external_receivers(root_node, source_bytes);
+        if !external_receivers.is_empty() {
+            fields.insert(
+                "external_receivers".to_string(),
+                Value::Array(external_receivers),
+            );
+        }
+
+        let inconsistent_receivers = inconsistent_receivers(root_node, source_bytes);
+        if !inconsistent_receivers.is_empty() {
+            fields.insert(
+                "inconsistent_receivers".to_string(),
+                Value::Object(inconsistent_receivers),
+            );
+        }
+
+        if fields.is_empty() {
+            None
+        } else {
+            Some(fields)
+        }
+    }
+}
+
+/// Expression of the file's `//go:build` line (`linux && !arm64`). Only
+/// comments before the `package` clause can constrain a build, as in
+/// `go/build`. The whole file is still indexed; this records what gates it.
+fn build_constraint(root_node: Node, source_bytes: &[u8]) -> Option<String> {
+    let mut cursor = root_node.walk();
+    root_node
+        .children(&mut cursor)
+        .take_while(|child| child.kind() != "package_clause")
+        .filter(|child| child.kind() == "comment")
+        .filter_map(|comment| comment.utf8_text(source_bytes).ok())
+        .find_map(|text| text.strip_prefix("//go:build"))
+        .filter(|rest| rest.is_empty() || rest.starts_with([' ', '\t']))
+        .map(|expr| expr.trim().to_string())
+        .filter(|expr| !expr.is_empty())
+}
+
+/// Name in the `package` clause. An external test file's `foo_test` is kept
+/// as written, so it stays a package apart from the `foo` it tests.
+fn package_name(root_node: Node, source_bytes: &[u8]) -> Option<String> {
+    let mut cursor = root_node.walk();
+    let package_clause = root_node
+        .children(&mut cursor)
+        .find(|child| child.kind() == "package_clause")?;
+    let mut clause_cursor = package_clause.walk();
+    let name = package_clause
+        .named_children(&mut clause_cursor)
+        .find(|child| child.kind() == "package_identifier")?;
+    name.utf8_text(source_bytes).ok().map(str::to_string)
+}
+
+/// The comment group directly above the `package` clause, with comment
+/// markers stripped. A blank line between the comments and the clause means
+/// they are not a doc comment, as in `go doc`.
+fn package_doc(root_node: Node, source_bytes: &[u8]) -> Option<String> {
+    let mut cursor = root_node.walk();
+    let package_clause = root_node
+        .children(&mut cursor)
+        .find(|child| child.kind() == "package_clause")?;
+
+    let mut comments = Vec::new();
+    let mut next_row = package_clause.start_position().row;
+    let mut node = package_clause.prev_sibling();
+    while let Some(comment) = node.filter(|n| n.kind() == "comment") {
+        if comment.end_position().row + 1 < next_row {
+            break;
+        }
+        comments.push(comment.utf8_text(source_bytes).ok()?);
+        next_row = comment.start_position().row;
+        node = comment.prev_sibling();
+    }
+    comments.reverse();
+
+    let doc = comments
+        .into_iter()
+        .flat_map(comment_lines)
+        .collect::<Vec<_>>()
+        .join("\n");
+    let doc = doc.trim();
+    (!doc.is_empty()).then(|| doc.to_string())
+}
+
+/// Text lines of one `//` or `/* */` comment, without the markers.
+pub(super) fn comment_lines(comment: &str) -> Vec<&str> {
+    if let Some(line) = comment.strip_prefix("//") {
+        return vec![line.strip_prefix(' ').unwrap_or(line).trim_end()];
+    }
+    let body = comment
+        .strip_prefix("/*")
+        .and_then(|rest| rest.strip_suffix("*/"))
+        .unwrap_or(comment);
+    body.lines().map(str::trim).collect()
+}
+
+/// Package selector -> import path for every import that binds a name, so a
+/// `redis.Client` in a signature can be expanded to its full path. Dot
+/// imports bind no selector and are returned separately; blank imports bind
+/// nothing and are skipped.
+fn import_selectors(
+    root_node: Node,
+    source_bytes: &[u8],
+) -> (serde_json::Map<String, Value>, Vec<Value>) {
+    let mut aliases = serde_json::Map::new();
+    let mut dot_imports = Vec::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "import_declaration" {
+            continue;
+        }
+        for spec in declaration_specs(decl, &["import_spec"], source_bytes) {
+            let Some(path) = extract_field_text(&spec, source_bytes, "path")
+                .map(|path| path.trim_matches(|c| c == '"' || c == '`').to_string())
+                .filter(|path| !path.is_empty())
+            else {
+                continue;
+            };
+            match spec.child_by_field_name("name") {
+                Some(name) if name.kind() == "dot" => dot_imports.push(Value::String(path)),
+                Some(name) if name.kind() == "blank_identifier" => {}
+                Some(name) => {
+                    if let Ok(alias) = name.utf8_text(source_bytes) {
+                        aliases.insert(alias.to_string(), Value::String(path));
+                    }
+                }
+                None => {
+                    aliases.insert(assumed_package_name(&path), Value::String(path));
+                }
+            }
+        }
+    }
+    (aliases, dot_imports)
+}
+
+/// The package name an unaliased import is assumed to bind, following
+/// goimports: the last path element, skipping a `/vN` major-version suffix,
+/// without a `go-` prefix and cut at the first non-identifier character.
+/// `github.com/redis/go-redis/v9` binds `redis`.
+pub(super) fn assumed_package_name(path: &str) -> String {
+    let mut segments = path.rsplit('/');
+    let mut base = segments.next().unwrap_or(path);
+    if let Some(version) = base.strip_prefix('v')
+        && !version.is_empty()
+        && version.bytes().all(|b| b.is_ascii_digit())
+        && let Some(parent) = segments.next()
+    {
+        base = parent;
+    }
+    let base = base.strip_prefix("go-").unwrap_or(base);
+    let end = base
+        .find(|c: char| !(c.is_alphanumeric() || c == '_'))
+        .unwrap_or(base.len());
+    base[..end].to_string()
+}
+
+/// Each parenthesized `const (...)` group as one logical unit: its exported
+/// names in declaration order and the line range of the whole block.
+fn const_blocks(root_node: Node, source_bytes: &[u8]) -> Vec<Value> {
+    let mut blocks = Vec::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "const_declaration" || !is_grouped(decl) {
+            continue;
+        }
+        let names: Vec<Value> = declaration_specs(decl, &["const_spec"], source_bytes)
+            .into_iter()
+            .flat_map(|spec| spec_names(spec, source_bytes))
+            .filter(|name| GoParser::is_exported(name))
+            .map(Value::String)
+            .collect();
+        if !names.is_empty() {
+            blocks.push(json!({
+                "names": names,
+                "lines": [decl.start_position().row + 1, decl.end_position().row + 1],
+            }));
+        }
+    }
+    blocks
+}
+
+/// Embedded (anonymous) fields per exported struct, written as in source
+/// (`*Base`, `io.Reader`), so they stay distinguishable from named fields.
+fn embedded_fields(root_node: Node, source_bytes: &[u8]) -> serde_json::Map<String, Value> {
+    let mut by_struct = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "type_declaration" {
+            continue;
+        }
+        for spec in declaration_specs(decl, &["type_spec"], source_bytes) {
+            let Some(name) = extract_field_text(&spec, source_bytes, "name")
+                .filter(|name| GoParser::is_exported(name))
+            else {
+                continue;
+            };
+            let embeds: Vec<Value> = struct_fields(spec, source_bytes)
+                .into_iter()
+                .filter(|field| field.embedded)
+                .filter_map(|field| embedded_field_text(field.node, source_bytes))
+                .map(Value::String)
+                .collect();
+            if !embeds.is_empty() {
+                by_struct.insert(name, Value::Array(embeds));
+            }
+        }
+    }
+    by_struct
+}
+
+/// Interfaces embedded by each exported interface, as written (`io.Reader`).
+fn embedded_interfaces_by_type(
+    root_node: Node,
+    source_bytes: &[u8],
+) -> serde_json::Map<String, Value> {
+    let mut by_interface = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "type_declaration" {
+            continue;
+        }
+        for spec in declaration_specs(decl, &["type_spec"], source_bytes) {
+            let Some(name) = extract_field_text(&spec, source_bytes, "name")
+                .filter(|name| GoParser::is_exported(name))
+            else {
+                continue;
+            };
+            let embeds: Vec<Value> = embedded_interfaces(spec, source_bytes)
+                .into_iter()
+                .map(Value::String)
+                .collect();
+            if !embeds.is_empty() {
+                by_interface.insert(name, Value::Array(embeds));
+            }
+        }
+    }
+    by_interface
+}
+
+/// Exported methods whose receiver type is not declared in this file, as
+/// `Receiver.Method`. They are indexed top-level rather than as members.
+fn external_receivers(root_node: Node, source_bytes: &[u8]) -> Vec<Value> {
+    let declared_types = declared_type_names(root_node, source_bytes);
+    let mut methods = Vec::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() == "method_declaration"
+            && !is_ignored(decl, source_bytes)
+            && let Some(name) = extract_field_text(&decl, source_bytes, "name")
+            && GoParser::is_exported(&name)
+            && let Some(receiver) = receiver_type_name(decl, source_bytes)
+            && !declared_types.contains(&receiver)
+        {
+            methods.push(Value::String(format!("{receiver}.{name}")));
+        }
+    }
+    methods
+}
+
+/// Functions `go test` runs, keyed by name: `test`, `benchmark`, `fuzz` or
+/// `example`. They are indexed with the `test` declaration kind.
+fn test_kinds(root_node: Node, source_bytes: &[u8]) -> serde_json::Map<String, Value> {
+    let mut kinds = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if let Some(kind) = test_kind(decl, source_bytes)
+            && let Some(name) = declaration_key(decl, source_bytes)
+        {
+            kinds.insert(name, Value::String(kind.to_string()));
+        }
+    }
+    kinds
+}
+
+/// Lines strictly between the braces of every function and method body, keyed
+/// by `name` or `Receiver.name`. Bodyless declarations (assembly stubs,
+/// `//go:linkname` forwards) and one-line bodies report 0.
+fn body_lines(root_node: Node, source_bytes: &[u8]) -> serde_json::Map<String, Value> {
+    let mut lines = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        let Some(key) = declaration_key(decl, source_bytes) else {
+            continue;
+        };
+        let count = decl.child_by_field_name("body").map_or(0, |body| {
+            (body.end_position().row - body.start_position().row).saturating_sub(1)
+        });
+        lines.insert(key, json!(count));
+    }
+    lines
+}
+
+fn is_grouped(decl: Node) -> bool {
+    let mut cursor = decl.walk();
+    decl.children(&mut cursor).any(|child| child.kind() == "(")
+}
@@ -0,0 +1,100 @@
+use super::GoParser;
+use super::custom_fields::comment_lines;
+use super::directives::is_ignored;
+use super::extract_exports::{declaration_specs, spec_names};
+use super::receivers::declaration_key;
+use crate::parser::builtin::query_helpers::extract_field_text;
+use serde_json::Value;
+use tree_sitter::Node;
+
+/// Exported declarations whose doc comment has a paragraph starting with
+/// `Deprecated:`, mapped to the rest of that paragraph (empty when the marker
+/// stands alone). Keys are `name` or `Receiver.name`. A `Deprecated:` later
+/// in a paragraph is an ordinary sentence, as in `go doc`. A spec in a
+/// parenthesized group is covered by its own doc comment or the group's.
+pub(super) fn deprecated(root_node: Node, source_bytes: &[u8]) -> serde_json::Map<String, Value> {
+    let mut notes = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        match decl.kind() {
+            "function_declaration" | "method_declaration" => {
+                if let Some(key) = declaration_key(decl, source_bytes)
+                    && extract_field_text(&decl, source_bytes, "name")
+                        .is_some_and(|name| GoParser::is_exported(&name))
+                    && let Some(note) = deprecation_note(decl, source_bytes)
+                {
+                    notes.insert(key, Value::String(note));
+                }
+            }
+            "type_declaration" | "const_declaration" | "var_declaration" => {
+                let group_note = deprecation_note(decl, source_bytes);
+                let kinds = ["type_spec", "type_alias", "const_spec", "var_spec"];
+                for spec in declaration_specs(decl, &kinds, source_bytes) {
+                    if is_ignored(spec, source_bytes) {
+                        continue;
+                    }
+                    let Some(note) =
+                        deprecation_note(spec, source_bytes).or_else(|| group_note.clone())
+                    else {
+                        continue;
+                    };
+                    let names = if spec.kind().starts_with("type_") {
+                        extract_field_text(&spec, source_bytes, "name")
+                            .into_iter()
+                            .collect()
+                    } else {
+                        spec_names(spec, source_bytes)
+                    };
+                    for name in names.into_iter().filter(|name| GoParser::is_exported(name)) {
+                        notes.insert(name, Value::String(note.clone()));
+                    }
+                }
+            }
+            _ => {}
+        }
+    }
+    notes
+}
+
+/// Text of the `Deprecated:` paragraph in the doc comment above `node`, with
+/// its lines joined by spaces.
+fn deprecation_note(node: Node, source_bytes: &[u8]) -> Option<String> {
+    let doc = doc_comment_lines(node, source_bytes);
+    doc.split(|line| line.trim().is_empty())
+        .find_map(|paragraph| {
+            let (first, rest) = paragraph.split_first()?;
+            let note = first.trim().strip_prefix("Deprecated:")?;
+            Some(
+                std::iter::once(note)
+                    .chain(rest.iter().copied())
+                    .map(str::trim)
+                    .filter(|line| !line.is_empty())
+                    .collect::<Vec<_>>()
+                    .join(" "),
+            )
+        })
+}
+
+/// Lines of the comment group ending on the line directly above `node`, in
+/// source order. A trailing comment on the previous line is not a doc comment.
+fn doc_comment_lines<'a>(node: Node, source_bytes: &'a [u8]) -> Vec<&'a str> {
+    let mut comments = Vec::new();
+    let mut next_row = node.start_position().row;
+    let mut prev = node.prev_sibling();
+    while let Some(comment) = prev.filter(|n| n.kind() == "comment") {
+        let trailing = comment
+            .prev_sibling()
+            .is_some_and(|before| before.end_position().row == comment.start_position().row);
+        if comment.end_position().row + 1 != next_row || trailing {
+            break;
+        }
+        let Ok(text) = comment.utf8_text(source_bytes) else {
+            break;
+        };
+        comments.push(text);
+        next_row = comment.start_position().row;
+        prev = comment.prev_sibling();
+    }
+    comments.reverse();
+    comments.into_iter().flat_map(comment_lines).collect()
+}
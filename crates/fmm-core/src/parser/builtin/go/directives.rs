@@ -0,0 +1,46 @@
+use tree_sitter::Node;
+
+/// Marks the declaration, spec or struct field below it as left out of the index.
+const IGNORE: &str = "fmm:ignore";
+/// Before the `package` clause, leaves the whole file out of the index.
+const IGNORE_FILE: &str = "fmm:ignore-file";
+
+/// Whether the comment group directly above `node` holds `//fmm:ignore`. A
+/// group declaration's directive covers every spec in it.
+pub(crate) fn is_ignored(node: Node, source_bytes: &[u8]) -> bool {
+    let mut next_row = node.start_position().row;
+    let mut prev = node.prev_sibling();
+    while let Some(comment) = prev.filter(|n| n.kind() == "comment") {
+        if comment.end_position().row + 1 != next_row {
+            break;
+        }
+        if comment
+            .utf8_text(source_bytes)
+            .is_ok_and(|text| is_directive(text, IGNORE))
+        {
+            return true;
+        }
+        next_row = comment.start_position().row;
+        prev = comment.prev_sibling();
+    }
+    false
+}
+
+/// Whether a `//fmm:ignore-file` comment appears before the `package` clause.
+pub(crate) fn is_ignored_file(root_node: Node, source_bytes: &[u8]) -> bool {
+    let mut cursor = root_node.walk();
+    root_node
+        .children(&mut cursor)
+        .take_while(|child| child.kind() != "package_clause")
+        .filter(|child| child.kind() == "comment")
+        .filter_map(|comment| comment.utf8_text(source_bytes).ok())
+        .any(|text| is_directive(text, IGNORE_FILE))
+}
+
+/// `//name`, optionally followed by a reason, as Go spells `//go:` directives.
+fn is_directive(comment: &str, name: &str) -> bool {
+    comment
+        .strip_prefix("//")
+        .and_then(|rest| rest.strip_prefix(name))
+        .is_some_and(|rest| rest.is_empty() || rest.starts_with([' ', '\t']))
+}
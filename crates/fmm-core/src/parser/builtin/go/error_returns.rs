@@ -0,0 +1,67 @@
+use super::extract_exports::declared_type_names;
+use super::receivers::declaration_key;
+use serde_json::{Value, json};
+use tree_sitter::Node;
+
+/// Functions and methods with the built-in `error` among their results, keyed
+/// as `name` / `Receiver.name`. `position` is the 0-based result slot of the
+/// first `error`, counting each name of a grouped `a, b int` result; `last`
+/// says whether the final result is `error`, as Go convention expects. Only
+/// the `error` identifier counts, not other types implementing it, and none
+/// does in a file that declares its own type named `error`.
+pub(super) fn error_returns(
+    root_node: Node,
+    source_bytes: &[u8],
+) -> serde_json::Map<String, Value> {
+    let mut returns = serde_json::Map::new();
+    if declared_type_names(root_node, source_bytes).contains("error") {
+        return returns;
+    }
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        let (Some(key), Some(result)) = (
+            declaration_key(decl, source_bytes),
+            decl.child_by_field_name("result"),
+        ) else {
+            continue;
+        };
+        let slots = result_types(result, source_bytes);
+        if let Some(position) = slots.iter().position(|ty| *ty == "error") {
+            returns.insert(
+                key,
+                json!({
+                    "position": position,
+                    "last": slots.last() == Some(&"error"),
+                }),
+            );
+        }
+    }
+    returns
+}
+
+/// Type of each result slot: one for an unparenthesized result, and one per
+/// name (or one for an unnamed entry) in a parameter list.
+fn result_types<'a>(result: Node, source_bytes: &'a [u8]) -> Vec<&'a str> {
+    if result.kind() != "parameter_list" {
+        return result.utf8_text(source_bytes).into_iter().collect();
+    }
+    let mut slots = Vec::new();
+    let mut cursor = result.walk();
+    for param in result.named_children(&mut cursor) {
+        if param.kind() != "parameter_declaration" {
+            continue;
+        }
+        let Some(ty) = param
+            .child_by_field_name("type")
+            .and_then(|ty| ty.utf8_text(source_bytes).ok())
+        else {
+            continue;
+        };
+        let mut name_cursor = param.walk();
+        let names = param
+            .children_by_field_name("name", &mut name_cursor)
+            .count();
+        slots.extend(std::iter::repeat_n(ty, names.max(1)));
+    }
+    slots
+}
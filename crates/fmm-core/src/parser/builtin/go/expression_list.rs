@@ -0,0 +1,35 @@
+use crate::parser::builtin::query_helpers::make_parser;
+use tree_sitter::Language;
+
+/// The expressions of a Go value list such as the right-hand side of a
+/// `const`/`var` signature, split on its top-level commas only, so
+/// `"a, b", f(1, 2)` is two values. None when `values` is not a valid
+/// expression list.
+pub fn split_expression_list(values: &str) -> Option<Vec<String>> {
+    let language: Language = tree_sitter_go::LANGUAGE.into();
+    let mut parser = make_parser(&language, "Go").ok()?;
+    let source = format!("package p\n\nvar _ = {values}\n");
+    let tree = parser.parse(&source, None)?;
+    let root_node = tree.root_node();
+    if root_node.has_error() {
+        return None;
+    }
+
+    let mut cursor = root_node.walk();
+    let decl = root_node
+        .named_children(&mut cursor)
+        .find(|child| child.kind() == "var_declaration")?;
+    let mut decl_cursor = decl.walk();
+    let spec = decl
+        .named_children(&mut decl_cursor)
+        .find(|child| child.kind() == "var_spec")?;
+    let expressions = spec.child_by_field_name("value")?;
+    let mut expr_cursor = expressions.walk();
+    let values = expressions
+        .named_children(&mut expr_cursor)
+        .filter(|expr| expr.kind() != "comment")
+        .filter_map(|expr| expr.utf8_text(source.as_bytes()).ok())
+        .map(str::to_string)
+        .collect();
+    Some(values)
+}
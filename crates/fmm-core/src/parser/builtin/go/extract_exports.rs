@@ -0,0 +1,335 @@
+use super::GoParser;
+use super::directives::is_ignored;
+use super::interface_elems::interface_methods;
+use super::receivers::receiver_type_name;
+use super::struct_fields::struct_fields;
+use super::symbol_metadata::{
+    go_entry, go_field_entry, go_method_entry, go_spec_entry, type_spec_kind,
+};
+use super::test_functions::test_kind;
+use crate::parser::builtin::query_helpers::extract_field_text;
+use crate::parser::{DeclarationKind, ExportEntry, SymbolVisibility};
+use std::collections::HashSet;
+use tree_sitter::Node;
+
+impl GoParser {
+    pub(super) fn extract_exports(&self, source: &str, root_node: Node) -> Vec<ExportEntry> {
+        let source_bytes = source.as_bytes();
+        let declared_types = declared_type_names(root_node, source_bytes);
+        let mut seen = HashSet::new();
+        let mut exports = Vec::new();
+
+        let mut cursor = root_node.walk();
+        for decl in root_node.children(&mut cursor) {
+            match decl.kind() {
+                "function_declaration" => {
+                    if !is_ignored(decl, source_bytes)
+                        && let Some(name) = extract_field_text(&decl, source_bytes, "name")
+                        && Self::is_exported(&name)
+                        && seen.insert(name.clone())
+                    {
+                        let kind = match test_kind(decl, source_bytes) {
+                            Some(_) => DeclarationKind::Test,
+                            None => DeclarationKind::Fn,
+                        };
+                        exports.push(go_entry(name, decl, source_bytes, kind));
+                    }
+                }
+                "method_declaration" => {
+                    Self::push_method(decl, source_bytes, &declared_types, &mut seen, &mut exports);
+                }
+                "type_declaration" => {
+                    for spec in declaration_specs(decl, &["type_spec", "type_alias"], source_bytes)
+                    {
+                        if let Some(name) = extract_field_text(&spec, source_bytes, "name")
+                            && Self::is_exported(&name)
+                            && seen.insert(name.clone())
+                        {
+                            let kind = type_spec_kind(spec);
+                            exports.push(go_spec_entry(
+                                name.clone(),
+                                spec,
+                                "type",
+                                source_bytes,
+                                kind,
+                            ));
+                            Self::push_exported_fields(
+                                name.clone(),
+                                spec,
+                                source_bytes,
+                                &mut exports,
+                            );
+                            Self::push_interface_methods(name, spec, source_bytes, &mut exports);
+                        }
+                    }
+                }
+                "const_declaration" => {
+                    Self::push_const_specs(
+                        decl,
+                        source_bytes,
+                        Self::is_exported,
+                        &mut seen,
+                        &mut exports,
+                    );
+                }
+                "var_declaration" => {
+                    for spec in declaration_specs(decl, &["var_spec"], source_bytes) {
+                        for name in spec_names(spec, source_bytes) {
+                            if Self::is_exported(&name) && seen.insert(name.clone()) {
+                                exports.push(go_spec_entry(
+                                    name,
+                                    spec,
+                                    "var",
+                                    source_bytes,
+                                    DeclarationKind::Var,
+                                ));
+                            }
+                        }
+                    }
+                }
+                _ => {}
+            }
+        }
+
+        exports.sort_by_key(|e| e.start_line);
+        exports
+    }
+
+    /// Exported methods nest under their receiver type when this file declares
+    /// it. A receiver declared elsewhere (another file of the package, or an
+    /// imported type behind an alias) leaves the method top-level as a
+    /// `Method`, with the receiver kept in its signature.
+    fn push_method(
+        decl: Node,
+        source_bytes: &[u8],
+        declared_types: &HashSet<String>,
+        seen: &mut HashSet<String>,
+        exports: &mut Vec<ExportEntry>,
+    ) {
+        let Some(name) = extract_field_text(&decl, source_bytes, "name") else {
+            return;
+        };
+        let Some(receiver) = receiver_type_name(decl, source_bytes) else {
+            return;
+        };
+        if !Self::is_exported(&name) || is_ignored(decl, source_bytes) {
+            return;
+        }
+
+        if declared_types.contains(&receiver) {
+            if Self::is_exported(&receiver) {
+                exports.push(go_method_entry(name, decl, source_bytes, receiver));
+            }
+        } else if seen.insert(name.clone()) {
+            exports.push(go_entry(name, decl, source_bytes, DeclarationKind::Method));
+        }
+    }
+
+    /// Exported fields of a struct type become members of it. Unexported
+    /// fields stay out of the index and are served on demand through the Go
+    /// private member extractor.
+    fn push_exported_fields(
+        parent: String,
+        spec: Node,
+        source_bytes: &[u8],
+        exports: &mut Vec<ExportEntry>,
+    ) {
+        for field in struct_fields(spec, source_bytes) {
+            if Self::is_exported(&field.name) {
+                exports.push(go_field_entry(
+                    field.name,
+                    field.node,
+                    source_bytes,
+                    parent.clone(),
+                ));
+            }
+        }
+    }
+
+    /// Exported methods of an interface's own method set become members of
+    /// it, signed as written in the interface body. Embedded interfaces are
+    /// recorded as `embedded_interfaces` rather than expanded, since their
+    /// methods are usually declared in another package.
+    fn push_interface_methods(
+        parent: String,
+        spec: Node,
+        source_bytes: &[u8],
+        exports: &mut Vec<ExportEntry>,
+    ) {
+        for method in interface_methods(spec, source_bytes) {
+            if Self::is_exported(&method.name) {
+                exports.push(go_method_entry(
+                    method.name,
+                    method.node,
+                    source_bytes,
+                    parent.clone(),
+                ));
+            }
+        }
+    }
+
+    /// A const spec without `= value` repeats the type and expression list of
+    /// the last spec that had one, exactly as the compiler does, so
+    /// `StatusInactive` in an iota block reads `const StatusInactive Status = iota`.
+    /// Only names passing `keep` are pushed.
+    fn push_const_specs(
+        decl: Node,
+        source_bytes: &[u8],
+        keep: fn(&str) -> bool,
+        seen: &mut HashSet<String>,
+        exports: &mut Vec<ExportEntry>,
+    ) {
+        let mut inherited: Option<(Option<String>, String)> = None;
+        for spec in declaration_specs(decl, &["const_spec"], source_bytes) {
+            let implicit = spec.child_by_field_name("value").is_none();
+            if let Some(value) = extract_field_text(&spec, source_bytes, "value") {
+                inherited = Some((extract_field_text(&spec, source_bytes, "type"), value));
+            }
+
+            let names = spec_names(spec, source_bytes);
+            for name in &names {
+                if !keep(name) || !seen.insert(name.clone()) {
+                    continue;
+                }
+                let mut entry = go_spec_entry(
+                    name.clone(),
+                    spec,
+                    "const",
+                    source_bytes,
+                    DeclarationKind::Const,
+                );
+                if implicit && let Some((ty, value)) = &inherited {
+                    entry.signature = Some(implicit_const_signature(&names, ty.as_deref(), value));
+                }
+                exports.push(entry);
+            }
+        }
+    }
+}
+
+/// Unexported package-level functions, types, consts and vars, which the index
+/// leaves out, for `--include-private`. Signatures and kinds are built as for
+/// exports, implicit const specs included. Methods belong to their receiver
+/// type and are left out, as are declarations under `//fmm:ignore`.
+pub(crate) fn unexported_declarations(root_node: Node, source_bytes: &[u8]) -> Vec<ExportEntry> {
+    let mut seen = HashSet::new();
+    let mut entries = Vec::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if is_ignored(decl, source_bytes) {
+            continue;
+        }
+        match decl.kind() {
+            "function_declaration" => {
+                if let Some(name) = extract_field_text(&decl, source_bytes, "name")
+                    && unexported(&name)
+                    && seen.insert(name.clone())
+                {
+                    entries.push(go_entry(name, decl, source_bytes, DeclarationKind::Fn));
+                }
+            }
+            "type_declaration" => {
+                for spec in declaration_specs(decl, &["type_spec", "type_alias"], source_bytes) {
+                    if let Some(name) = extract_field_text(&spec, source_bytes, "name")
+                        && unexported(&name)
+                        && seen.insert(name.clone())
+                    {
+                        let kind = type_spec_kind(spec);
+                        entries.push(go_spec_entry(name, spec, "type", source_bytes, kind));
+                    }
+                }
+            }
+            "const_declaration" => {
+                GoParser::push_const_specs(decl, source_bytes, unexported, &mut seen, &mut entries);
+            }
+            "var_declaration" => {
+                for spec in declaration_specs(decl, &["var_spec"], source_bytes) {
+                    for name in spec_names(spec, source_bytes) {
+                        if unexported(&name) && seen.insert(name.clone()) {
+                            entries.push(go_spec_entry(
+                                name,
+                                spec,
+                                "var",
+                                source_bytes,
+                                DeclarationKind::Var,
+                            ));
+                        }
+                    }
+                }
+            }
+            _ => {}
+        }
+    }
+    for entry in &mut entries {
+        entry.visibility = Some(SymbolVisibility::NonExported);
+    }
+    entries.sort_by_key(|e| e.start_line);
+    entries
+}
+
+/// A lowercase name other than the blank identifier.
+fn unexported(name: &str) -> bool {
+    name != "_" && !GoParser::is_exported(name)
+}
+
+fn implicit_const_signature(names: &[String], ty: Option<&str>, value: &str) -> String {
+    match ty {
+        Some(ty) => format!("const {} {} = {}", names.join(", "), ty, value),
+        None => format!("const {} = {}", names.join(", "), value),
+    }
+}
+
+/// Every type name declared at the top level of the file, exported or not.
+pub(super) fn declared_type_names(root_node: Node, source_bytes: &[u8]) -> HashSet<String> {
+    let mut names = HashSet::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "type_declaration" {
+            continue;
+        }
+        for spec in declaration_specs(decl, &["type_spec", "type_alias"], source_bytes) {
+            if let Some(name) = extract_field_text(&spec, source_bytes, "name") {
+                names.insert(name);
+            }
+        }
+    }
+    names
+}
+
+/// Specs of a declaration, whether written singly (`const X = 1`) or grouped
+/// in parentheses. Newer grammars wrap grouped vars in a `var_spec_list`.
+/// Specs under `//fmm:ignore`, or in a group under it, are left out.
+pub(super) fn declaration_specs<'tree>(
+    decl: Node<'tree>,
+    kinds: &[&str],
+    source_bytes: &[u8],
+) -> Vec<Node<'tree>> {
+    let mut specs = Vec::new();
+    if is_ignored(decl, source_bytes) {
+        return specs;
+    }
+    let mut cursor = decl.walk();
+    for child in decl.children(&mut cursor) {
+        if kinds.contains(&child.kind()) {
+            specs.push(child);
+        } else if child.kind().ends_with("_spec_list") {
+            let mut list_cursor = child.walk();
+            specs.extend(
+                child
+                    .children(&mut list_cursor)
+                    .filter(|spec| kinds.contains(&spec.kind())),
+            );
+        }
+    }
+    specs.retain(|spec| !is_ignored(*spec, source_bytes));
+    specs
+}
+
+/// All names bound by a `const_spec`/`var_spec` (`const A, B = 1, 2`).
+pub(super) fn spec_names(spec: Node, source_bytes: &[u8]) -> Vec<String> {
+    let mut cursor = spec.walk();
+    spec.children_by_field_name("name", &mut cursor)
+        .filter_map(|name| name.utf8_text(source_bytes).ok())
+        .map(str::to_string)
+        .collect()
+}
@@ -0,0 +1,64 @@
+use super::GoParser;
+use std::collections::HashSet;
+use streaming_iterator::StreamingIterator;
+use tree_sitter::{Node, QueryCursor};
+
+impl GoParser {
+    pub(super) fn extract_imports(
+        &self,
+        source: &str,
+        root_node: Node,
+    ) -> (Vec<String>, Vec<String>) {
+        let mut import_set = HashSet::new();
+        let mut dependency_set = HashSet::new();
+        let source_bytes = source.as_bytes();
+
+        let mut cursor = QueryCursor::new();
+        let mut iter = cursor.matches(&self.import_query, root_node, source_bytes);
+        while let Some(m) = iter.next() {
+            for capture in m.captures {
+                if let Ok(text) = capture.node.utf8_text(source_bytes) {
+                    let path = text.trim_matches('"');
+                    if path.is_empty() {
+                        continue;
+                    }
+
+                    // ALP-797: three-way classification using the module name from go.mod.
+                    //
+                    // When the module name is known:
+                    //   - Same-module paths (start with module_name + "/") → dependencies
+                    //     The module prefix is stripped so dep_matches can resolve them
+                    //     against manifest file paths (which are relative to project root).
+                    //   - Third-party domain-qualified paths → imports (external)
+                    //   - Stdlib paths (no dot in root segment) → imports (external)
+                    //
+                    // Fallback when go.mod not found: original dot-in-root-segment heuristic.
+                    // ALL domain-qualified paths go to dependencies (old behaviour), which
+                    // preserves the pre-ALP-795 output for projects without go.mod.
+                    let root_pkg = path.split('/').next().unwrap_or(path);
+                    if let Some(ref module) = self.module_name {
+                        let prefix = format!("{}/", module);
+                        if let Some(local_path) = path.strip_prefix(&prefix) {
+                            // Same-module import: store the intra-module relative path.
+                            dependency_set.insert(local_path.to_string());
+                        } else {
+                            // Stdlib (no dot) or third-party → external.
+                            import_set.insert(path.to_string());
+                        }
+                    } else if root_pkg.contains('.') {
+                        // Fallback: no go.mod — domain-qualified → dependencies (legacy).
+                        dependency_set.insert(path.to_string());
+                    } else {
+                        import_set.insert(path.to_string());
+                    }
+                }
+            }
+        }
+
+        let mut imports: Vec<String> = import_set.into_iter().collect();
+        let mut dependencies: Vec<String> = dependency_set.into_iter().collect();
+        imports.sort();
+        dependencies.sort();
+        (imports, dependencies)
+    }
+}
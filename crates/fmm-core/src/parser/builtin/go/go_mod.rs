@@ -0,0 +1,62 @@
+use std::path::Path;
+
+/// Walk up from `file_path`'s directory looking for `go.mod`. When found,
+/// return the directory holding it and the module name from the `module`
+/// directive.
+pub(super) fn find_go_mod(file_path: &Path) -> Option<(&Path, String)> {
+    let mut dir = file_path.parent();
+    while let Some(d) = dir {
+        let go_mod = d.join("go.mod");
+        if go_mod.exists()
+            && let Ok(content) = std::fs::read_to_string(&go_mod)
+        {
+            return extract_module_name(&content).map(|module| (d, module));
+        }
+        dir = d.parent();
+    }
+    None
+}
+
+/// Import path of the package holding `file_path`: the module name joined
+/// with the file's directory relative to `module_dir`, so
+/// `internal/server/server.go` in `example.com/mod` is
+/// `example.com/mod/internal/server`.
+pub(super) fn package_import_path(
+    module: &str,
+    module_dir: &Path,
+    file_path: &Path,
+) -> Option<String> {
+    let relative = file_path.parent()?.strip_prefix(module_dir).ok()?;
+    let segments: Vec<_> = relative
+        .components()
+        .map(|component| component.as_os_str().to_string_lossy())
+        .collect();
+    if segments.is_empty() {
+        Some(module.to_string())
+    } else {
+        Some(format!("{module}/{}", segments.join("/")))
+    }
+}
+
+/// Extract the module name from go.mod content.
+/// The `module` directive is always the first non-comment, non-empty line.
+pub(super) fn extract_module_name(content: &str) -> Option<String> {
+    for line in content.lines() {
+        let trimmed = line.trim();
+        if trimmed.is_empty() || trimmed.starts_with("//") {
+            continue;
+        }
+        if let Some(rest) = trimmed.strip_prefix("module") {
+            let name = rest.trim();
+            if !name.is_empty() {
+                // Strip any inline comment.
+                let name = name.split_whitespace().next().unwrap_or(name);
+                return Some(name.to_string());
+            }
+        }
+        // Any non-empty, non-comment line that isn't `module` means we've
+        // passed the preamble without finding it.
+        break;
+    }
+    None
+}
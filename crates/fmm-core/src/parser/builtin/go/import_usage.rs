@@ -0,0 +1,94 @@
+use super::custom_fields::assumed_package_name;
+use super::extract_exports::declaration_specs;
+use crate::parser::builtin::query_helpers::extract_field_text;
+use serde_json::Value;
+use std::collections::HashSet;
+use tree_sitter::Node;
+
+/// Each import path classified as `stdlib`, `third_party` or `module`. With a
+/// go.mod, paths under its module are `module`; everything else is
+/// `third_party` when its first segment has a dot (`github.com/...`) and
+/// `stdlib` otherwise.
+pub(super) fn import_kinds(
+    root_node: Node,
+    source_bytes: &[u8],
+    module_name: Option<&str>,
+) -> serde_json::Map<String, Value> {
+    let mut kinds = serde_json::Map::new();
+    for (_, path) in import_specs(root_node, source_bytes) {
+        let in_module = module_name.is_some_and(|module| {
+            path == module
+                || path
+                    .strip_prefix(module)
+                    .is_some_and(|rest| rest.starts_with('/'))
+        });
+        let kind = if in_module {
+            "module"
+        } else if path.split('/').next().unwrap_or(&path).contains('.') {
+            "third_party"
+        } else {
+            "stdlib"
+        };
+        kinds.insert(path, Value::String(kind.to_string()));
+    }
+    kinds
+}
+
+/// Import paths whose package name is never used as a selector
+/// (`json.Marshal`, `http.Handler`). Blank and dot imports are left out, as
+/// they bind no name to check. An unaliased import is assumed to bind the
+/// name goimports would, so a package whose name differs from its path is
+/// reported until it is aliased.
+pub(super) fn unused_imports(root_node: Node, source_bytes: &[u8]) -> Vec<Value> {
+    let mut used = HashSet::new();
+    collect_selectors(root_node, source_bytes, &mut used);
+    import_specs(root_node, source_bytes)
+        .into_iter()
+        .filter_map(|(spec, path)| {
+            let name = match spec.child_by_field_name("name") {
+                Some(name) if matches!(name.kind(), "dot" | "blank_identifier") => return None,
+                Some(name) => name.utf8_text(source_bytes).ok()?.to_string(),
+                None => assumed_package_name(&path),
+            };
+            (!used.contains(&name)).then_some(Value::String(path))
+        })
+        .collect()
+}
+
+/// Every `import_spec` with its unquoted path, in source order.
+fn import_specs<'tree>(root_node: Node<'tree>, source_bytes: &[u8]) -> Vec<(Node<'tree>, String)> {
+    let mut specs = Vec::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "import_declaration" {
+            continue;
+        }
+        for spec in declaration_specs(decl, &["import_spec"], source_bytes) {
+            if let Some(path) = extract_field_text(&spec, source_bytes, "path")
+                .map(|path| path.trim_matches(|c| c == '"' || c == '`').to_string())
+                .filter(|path| !path.is_empty())
+            {
+                specs.push((spec, path));
+            }
+        }
+    }
+    specs
+}
+
+/// Identifiers used as the package of a selector or qualified type.
+fn collect_selectors(node: Node, source_bytes: &[u8], used: &mut HashSet<String>) {
+    let package = match node.kind() {
+        "selector_expression" => node
+            .child_by_field_name("operand")
+            .filter(|operand| operand.kind() == "identifier"),
+        "qualified_type" => node.child_by_field_name("package"),
+        _ => None,
+    };
+    if let Some(text) = package.and_then(|package| package.utf8_text(source_bytes).ok()) {
+        used.insert(text.to_string());
+    }
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        collect_selectors(child, source_bytes, used);
+    }
+}
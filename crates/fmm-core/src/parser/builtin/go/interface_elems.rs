@@ -0,0 +1,61 @@
+use super::directives::is_ignored;
+use tree_sitter::Node;
+
+/// One method of an interface's own method set: `Read(p []byte) (int, error)`.
+pub(crate) struct InterfaceMethod<'tree> {
+    pub(crate) name: String,
+    pub(crate) node: Node<'tree>,
+}
+
+/// Methods declared directly in a `type_spec` whose type is an interface.
+/// Empty for any other spec. Promoted methods of embedded interfaces are not
+/// included; see [`embedded_interfaces`]. Methods under `//fmm:ignore` are
+/// left out.
+pub(crate) fn interface_methods<'tree>(
+    spec: Node<'tree>,
+    source_bytes: &[u8],
+) -> Vec<InterfaceMethod<'tree>> {
+    interface_elems(spec)
+        .into_iter()
+        .filter(|elem| elem.kind() == "method_elem" && !is_ignored(*elem, source_bytes))
+        .filter_map(|elem| {
+            let name = elem
+                .child_by_field_name("name")?
+                .utf8_text(source_bytes)
+                .ok()?;
+            Some(InterfaceMethod {
+                name: name.to_string(),
+                node: elem,
+            })
+        })
+        .collect()
+}
+
+/// Interfaces embedded by name (`io.Reader`, `Closer`), as written. Type set
+/// constraints such as `~int | ~string` are not embeds and are skipped.
+pub(super) fn embedded_interfaces(spec: Node, source_bytes: &[u8]) -> Vec<String> {
+    interface_elems(spec)
+        .into_iter()
+        .filter(|elem| elem.kind() == "type_elem" && elem.named_child_count() == 1)
+        .filter_map(|elem| elem.named_child(0))
+        .filter(|ty| {
+            matches!(
+                ty.kind(),
+                "type_identifier" | "qualified_type" | "generic_type"
+            )
+        })
+        .filter_map(|ty| ty.utf8_text(source_bytes).ok())
+        .map(str::to_string)
+        .collect()
+}
+
+fn interface_elems(spec: Node) -> Vec<Node> {
+    let Some(interface) = spec
+        .child_by_field_name("type")
+        .filter(|ty| ty.kind() == "interface_type")
+    else {
+        return Vec::new();
+    };
+    let mut cursor = interface.walk();
+    interface.named_children(&mut cursor).collect()
+}
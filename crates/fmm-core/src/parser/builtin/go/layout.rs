@@ -0,0 +1,274 @@
+use super::extract_exports::declaration_specs;
+use crate::parser::builtin::query_helpers::extract_field_text;
+use serde_json::{Value, json};
+use std::collections::HashMap;
+use tree_sitter::Node;
+
+/// Size and alignment of a type, in bytes.
+#[derive(Clone, Copy)]
+struct Layout {
+    size: u64,
+    align: u64,
+}
+
+/// Estimated memory layout of every struct type declared in the file, using
+/// the host's word size and the gc compiler's alignment rules. Each struct
+/// reports its `size`, `align` and per-field `offset`/`size`, with `padding`
+/// on fields followed by wasted bytes. When ordering fields by decreasing
+/// alignment would shrink the struct, `suggested_order` and `suggested_size`
+/// give that order. A struct with a field whose size cannot be known from
+/// this file alone (an imported or generic type) reports `size: "unknown"`
+/// and names those fields instead of guessing.
+pub(super) fn struct_layouts(
+    root_node: Node,
+    source_bytes: &[u8],
+) -> serde_json::Map<String, Value> {
+    let resolver = Resolver::new(root_node, source_bytes);
+    let mut layouts = serde_json::Map::new();
+    for (name, ty) in &resolver.types {
+        if ty.kind() != "struct_type" {
+            continue;
+        }
+        let fields = resolver.fields(*ty, &mut vec![name.clone()]);
+        if fields.is_empty() {
+            continue;
+        }
+        layouts.insert(name.clone(), describe(&fields));
+    }
+    layouts
+}
+
+fn describe(fields: &[(String, Option<Layout>)]) -> Value {
+    let unknown: Vec<&str> = fields
+        .iter()
+        .filter(|(_, layout)| layout.is_none())
+        .map(|(name, _)| name.as_str())
+        .collect();
+    if !unknown.is_empty() {
+        return json!({ "size": "unknown", "unknown_fields": unknown });
+    }
+    let known: Vec<(&str, Layout)> = fields
+        .iter()
+        .filter_map(|(name, layout)| Some((name.as_str(), (*layout)?)))
+        .collect();
+    let (total, offsets) = place(known.iter().map(|(_, layout)| *layout));
+
+    let mut entries = Vec::new();
+    for (i, (name, layout)) in known.iter().enumerate() {
+        let end = offsets[i] + layout.size;
+        let next = offsets.get(i + 1).copied().unwrap_or(total.size);
+        let mut entry = json!({ "name": name, "offset": offsets[i], "size": layout.size });
+        if next > end {
+            entry["padding"] = json!(next - end);
+        }
+        entries.push(entry);
+    }
+    let mut described = json!({ "size": total.size, "align": total.align, "fields": entries });
+
+    let mut reordered = known.clone();
+    reordered.sort_by(|(_, a), (_, b)| b.align.cmp(&a.align));
+    let (reordered_total, _) = place(reordered.iter().map(|(_, layout)| *layout));
+    if reordered_total.size < total.size {
+        described["suggested_order"] =
+            json!(reordered.iter().map(|(name, _)| *name).collect::<Vec<_>>());
+        described["suggested_size"] = json!(reordered_total.size);
+    }
+    described
+}
+
+/// Struct layout of fields in order: the whole struct and each field offset.
+/// A trailing zero-size field gets a byte of its own, as gc does, so taking
+/// its address cannot point past the struct.
+fn place(fields: impl Iterator<Item = Layout>) -> (Layout, Vec<u64>) {
+    let mut offset = 0;
+    let mut align = 1;
+    let mut offsets = Vec::new();
+    let mut last_size = None;
+    for field in fields {
+        offset = align_up(offset, field.align);
+        offsets.push(offset);
+        offset += field.size;
+        align = align.max(field.align);
+        last_size = Some(field.size);
+    }
+    if last_size == Some(0) && offset > 0 {
+        offset += 1;
+    }
+    let size = align_up(offset, align);
+    (Layout { size, align }, offsets)
+}
+
+fn align_up(offset: u64, align: u64) -> u64 {
+    offset.div_ceil(align) * align
+}
+
+struct Resolver<'a, 'tree> {
+    /// Type expression of every non-generic type declared in the file, in
+    /// source order.
+    types: Vec<(String, Node<'tree>)>,
+    by_name: HashMap<String, Node<'tree>>,
+    source_bytes: &'a [u8],
+    word: u64,
+}
+
+impl<'a, 'tree> Resolver<'a, 'tree> {
+    fn new(root_node: Node<'tree>, source_bytes: &'a [u8]) -> Self {
+        let mut types = Vec::new();
+        let mut cursor = root_node.walk();
+        for decl in root_node.children(&mut cursor) {
+            if decl.kind() != "type_declaration" {
+                continue;
+            }
+            for spec in declaration_specs(decl, &["type_spec", "type_alias"], source_bytes) {
+                // Generic types have no layout until instantiated.
+                if spec.child_by_field_name("type_parameters").is_some() {
+                    continue;
+                }
+                if let Some(name) = extract_field_text(&spec, source_bytes, "name")
+                    && let Some(ty) = spec.child_by_field_name("type")
+                {
+                    types.push((name, ty));
+                }
+            }
+        }
+        let by_name = types.iter().cloned().collect();
+        Self {
+            types,
+            by_name,
+            source_bytes,
+            word: std::mem::size_of::<usize>() as u64,
+        }
+    }
+
+    /// Each field of a `struct_type` with its layout, None when unknown.
+    /// `visiting` guards against a struct containing itself by value.
+    fn fields(&self, ty: Node, visiting: &mut Vec<String>) -> Vec<(String, Option<Layout>)> {
+        let mut fields = Vec::new();
+        let mut cursor = ty.walk();
+        let Some(body) = ty
+            .children(&mut cursor)
+            .find(|child| child.kind() == "field_declaration_list")
+        else {
+            return fields;
+        };
+        let mut cursor = body.walk();
+        for decl in body.children(&mut cursor) {
+            if decl.kind() != "field_declaration" {
+                continue;
+            }
+            let Some(field_type) = decl.child_by_field_name("type") else {
+                continue;
+            };
+            let mut name_cursor = decl.walk();
+            let names: Vec<String> = decl
+                .children_by_field_name("name", &mut name_cursor)
+                .filter_map(|name| name.utf8_text(self.source_bytes).ok())
+                .map(str::to_string)
+                .collect();
+            if names.is_empty() {
+                // Embedded field: `*Base` is a pointer, `Base` is the type inline.
+                let mut child_cursor = decl.walk();
+                let pointer = decl
+                    .children(&mut child_cursor)
+                    .any(|child| child.kind() == "*");
+                let layout = if pointer {
+                    Some(self.word_layout(1))
+                } else {
+                    self.layout(field_type, visiting)
+                };
+                let name = field_type
+                    .utf8_text(self.source_bytes)
+                    .unwrap_or_default()
+                    .rsplit('.')
+                    .next()
+                    .unwrap_or_default()
+                    .to_string();
+                fields.push((name, layout));
+                continue;
+            }
+            let layout = self.layout(field_type, visiting);
+            fields.extend(names.into_iter().map(|name| (name, layout)));
+        }
+        fields
+    }
+
+    fn layout(&self, ty: Node, visiting: &mut Vec<String>) -> Option<Layout> {
+        match ty.kind() {
+            "type_identifier" => {
+                let name = ty.utf8_text(self.source_bytes).ok()?;
+                let Some(declared) = self.by_name.get(name).copied() else {
+                    return self.predeclared(name);
+                };
+                if visiting.iter().any(|seen| seen == name) {
+                    return None;
+                }
+                visiting.push(name.to_string());
+                let layout = self.layout(declared, visiting);
+                visiting.pop();
+                layout
+            }
+            "qualified_type" => (ty.utf8_text(self.source_bytes).ok()? == "unsafe.Pointer")
+                .then(|| self.word_layout(1)),
+            "pointer_type" | "map_type" | "channel_type" | "function_type" => {
+                Some(self.word_layout(1))
+            }
+            "slice_type" => Some(self.word_layout(3)),
+            "interface_type" => Some(self.word_layout(2)),
+            "array_type" => {
+                let length = ty
+                    .child_by_field_name("length")
+                    .filter(|length| length.kind() == "int_literal")?
+                    .utf8_text(self.source_bytes)
+                    .ok()?
+                    .replace('_', "")
+                    .parse::<u64>()
+                    .ok()?;
+                let element = self.layout(ty.child_by_field_name("element")?, visiting)?;
+                Some(Layout {
+                    size: element.size.checked_mul(length)?,
+                    align: element.align,
+                })
+            }
+            "struct_type" => {
+                let fields = self.fields(ty, visiting);
+                let layouts: Option<Vec<Layout>> =
+                    fields.into_iter().map(|(_, layout)| layout).collect();
+                Some(place(layouts?.into_iter()).0)
+            }
+            "parenthesized_type" => {
+                let mut cursor = ty.walk();
+                let inner = ty.named_children(&mut cursor).next()?;
+                self.layout(inner, visiting)
+            }
+            _ => None,
+        }
+    }
+
+    /// Layout of Go's predeclared types. 64-bit values align to the word on
+    /// 32-bit hosts, as gc lays them out.
+    fn predeclared(&self, name: &str) -> Option<Layout> {
+        let fixed = |size: u64, align: u64| Layout {
+            size,
+            align: align.min(self.word),
+        };
+        Some(match name {
+            "bool" | "int8" | "uint8" | "byte" => fixed(1, 1),
+            "int16" | "uint16" => fixed(2, 2),
+            "int32" | "uint32" | "rune" | "float32" => fixed(4, 4),
+            "int64" | "uint64" | "float64" => fixed(8, 8),
+            "complex64" => fixed(8, 4),
+            "complex128" => fixed(16, 8),
+            "int" | "uint" | "uintptr" => self.word_layout(1),
+            "string" => self.word_layout(2),
+            "error" | "any" => self.word_layout(2),
+            _ => return None,
+        })
+    }
+
+    fn word_layout(&self, words: u64) -> Layout {
+        Layout {
+            size: words * self.word,
+            align: self.word,
+        }
+    }
+}
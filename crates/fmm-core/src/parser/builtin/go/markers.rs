@@ -0,0 +1,104 @@
+use super::directives::{is_ignored, is_ignored_file};
+use super::receivers::declaration_key;
+use crate::parser::builtin::query_helpers::make_parser;
+use serde_json::{Value, json};
+use tree_sitter::{Language, Node};
+
+/// Tags treated as tech-debt markers.
+const MARKER_TAGS: &[&str] = &["TODO", "FIXME", "HACK"];
+
+/// `TODO`/`FIXME`/`HACK` markers in line and block comments, in source
+/// order. Markers inside a function or method body carry its key (`Process`,
+/// `Handler.validate`); markers anywhere else are package scope and carry none.
+/// Declarations under `//fmm:ignore` are skipped along with their markers.
+pub(super) fn markers(root_node: Node, source_bytes: &[u8]) -> Vec<Value> {
+    tagged_markers(root_node, source_bytes, MARKER_TAGS)
+}
+
+/// The `markers` field of `source`, parsed afresh so `extra_tags` (`XXX`,
+/// `NOTE`) count alongside `TODO`, `FIXME` and `HACK`.
+pub fn markers_with_tags(source: &str, extra_tags: &[String]) -> Vec<Value> {
+    let language: Language = tree_sitter_go::LANGUAGE.into();
+    let Some(tree) = make_parser(&language, "Go")
+        .ok()
+        .and_then(|mut parser| parser.parse(source, None))
+    else {
+        return Vec::new();
+    };
+    let root_node = tree.root_node();
+    if is_ignored_file(root_node, source.as_bytes()) {
+        return Vec::new();
+    }
+    let mut tags: Vec<&str> = MARKER_TAGS.to_vec();
+    for tag in extra_tags {
+        if !tags.contains(&tag.as_str()) {
+            tags.push(tag.as_str());
+        }
+    }
+    tagged_markers(root_node, source.as_bytes(), &tags)
+}
+
+fn tagged_markers(root_node: Node, source_bytes: &[u8], tags: &[&str]) -> Vec<Value> {
+    let mut found = Vec::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if is_ignored(decl, source_bytes) {
+            continue;
+        }
+        let function = declaration_key(decl, source_bytes);
+        let mut comments = Vec::new();
+        collect_comments(decl, &mut comments);
+        for comment in comments {
+            let Ok(text) = comment.utf8_text(source_bytes) else {
+                continue;
+            };
+            let first_line = comment.start_position().row + 1;
+            for (offset, line) in text.lines().enumerate() {
+                let Some((tag, rest)) = find_marker(line, tags) else {
+                    continue;
+                };
+                let mut marker = json!({
+                    "tag": tag,
+                    "line": first_line + offset,
+                    "text": rest,
+                });
+                if let Some(function) = &function {
+                    marker["function"] = json!(function);
+                }
+                found.push(marker);
+            }
+        }
+    }
+    found
+}
+
+fn collect_comments<'tree>(node: Node<'tree>, comments: &mut Vec<Node<'tree>>) {
+    if node.kind() == "comment" {
+        comments.push(node);
+        return;
+    }
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        collect_comments(child, comments);
+    }
+}
+
+/// A marker tag appearing on `line` as a whole word, and the comment text
+/// after it with the `:` separator trimmed. `TODO(alice): x` keeps `(alice): x`.
+fn find_marker<'tag>(line: &str, tags: &[&'tag str]) -> Option<(&'tag str, String)> {
+    tags.iter().find_map(|&tag| {
+        line.match_indices(tag).find_map(|(start, _)| {
+            let before = line[..start].chars().next_back();
+            let after = &line[start + tag.len()..];
+            let is_word = |c: Option<char>| c.is_some_and(|c| c.is_alphanumeric() || c == '_');
+            if is_word(before) || is_word(after.chars().next()) {
+                return None;
+            }
+            let text = after
+                .trim_start_matches(|c: char| c == ':' || c.is_whitespace())
+                .trim_end_matches("*/")
+                .trim();
+            Some((tag, text.to_string()))
+        })
+    })
+}
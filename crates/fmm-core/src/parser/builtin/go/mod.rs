@@ -0,0 +1,147 @@
+mod calls;
+mod complexity;
+mod const_values;
+mod custom_fields;
+mod deprecated;
+mod directives;
+mod error_returns;
+mod expression_list;
+mod extract_exports;
+mod extract_imports;
+mod go_mod;
+mod import_usage;
+mod interface_elems;
+mod layout;
+mod markers;
+mod qualify;
+mod receivers;
+mod shadowing;
+mod special_functions;
+mod struct_fields;
+mod symbol_metadata;
+mod test_functions;
+mod undocumented;
+pub(crate) use directives::{is_ignored, is_ignored_file};
+pub use expression_list::split_expression_list;
+pub(crate) use extract_exports::unexported_declarations;
+pub(crate) use interface_elems::interface_methods;
+pub use markers::markers_with_tags;
+pub use qualify::qualify_selectors;
+pub(crate) use receivers::receiver_type_name;
+pub(crate) use struct_fields::struct_fields;
+pub(crate) use symbol_metadata::{go_field_entry, go_method_entry};
+
+#[cfg(test)]
+mod tests;
+
+use super::query_helpers::{compile_query, first_syntax_error, make_parser};
+use crate::parser::{Metadata, ParseResult, Parser};
+use anyhow::Result;
+use std::path::Path;
+use tree_sitter::{Language, Parser as TSParser, Query};
+
+pub struct GoParser {
+    parser: TSParser,
+    import_query: Query,
+    /// ALP-796: module name extracted from go.mod (e.g. "github.com/myorg/proj").
+    /// None when no go.mod is found — triggers fallback classification heuristic.
+    module_name: Option<String>,
+    /// Import path of the package being parsed, derived from go.mod and the
+    /// file's directory. None without a go.mod.
+    import_path: Option<String>,
+}
+
+impl GoParser {
+    pub fn new() -> Result<Self> {
+        let language: Language = tree_sitter_go::LANGUAGE.into();
+        let parser = make_parser(&language, "Go")?;
+
+        let import_query = compile_query(
+            &language,
+            "(import_spec path: (interpreted_string_literal) @path)",
+            "import",
+        )?;
+
+        Ok(Self {
+            parser,
+            import_query,
+            module_name: None,
+            import_path: None,
+        })
+    }
+
+    fn is_exported(name: &str) -> bool {
+        name.starts_with(|c: char| c.is_uppercase())
+    }
+}
+
+impl Parser for GoParser {
+    fn parse(&mut self, source: &str) -> Result<ParseResult> {
+        let tree = self
+            .parser
+            .parse(source, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Go source"))?;
+
+        let root_node = tree.root_node();
+        let loc = source.lines().count();
+        // An ignored file keeps its line count and nothing else.
+        if directives::is_ignored_file(root_node, source.as_bytes()) {
+            return Ok(ParseResult {
+                metadata: Metadata {
+                    loc,
+                    ..Default::default()
+                },
+                custom_fields: None,
+            });
+        }
+
+        let exports = self.extract_exports(source, root_node);
+        let (imports, dependencies) = self.extract_imports(source, root_node);
+        let custom_fields = self.extract_custom_fields(root_node, source.as_bytes());
+
+        Ok(ParseResult {
+            metadata: Metadata {
+                exports,
+                imports,
+                dependencies,
+                loc,
+                ..Default::default()
+            },
+            custom_fields,
+        })
+    }
+
+    /// ALP-796: override parse_file() to load the module name from go.mod before parsing.
+    fn parse_file(&mut self, source: &str, file_path: &Path) -> Result<ParseResult> {
+        let go_mod = go_mod::find_go_mod(file_path);
+        self.import_path = go_mod.as_ref().and_then(|(module_dir, module)| {
+            go_mod::package_import_path(module, module_dir, file_path)
+        });
+        self.module_name = go_mod.map(|(_, module)| module);
+        self.parse(source)
+    }
+
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
+    fn language_id(&self) -> &'static str {
+        "go"
+    }
+
+    fn extensions(&self) -> &'static [&'static str] {
+        &["go"]
+    }
+}
+
+pub(crate) const DESCRIPTOR: crate::parser::RegisteredLanguage =
+    crate::parser::RegisteredLanguage {
+        language_id: "go",
+        extensions: &["go"],
+        reexport_filenames: &[],
+        test_patterns: crate::parser::LanguageTestPatterns {
+            filename_suffixes: &["_test.go"],
+            filename_prefixes: &[],
+            test_symbol_prefixes: &["Test"],
+        },
+    };
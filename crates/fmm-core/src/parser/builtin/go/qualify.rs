@@ -0,0 +1,58 @@
+use serde_json::{Map, Value};
+
+/// `text`, a Go signature, with every package selector `pkg.Name` whose `pkg`
+/// is a key of `aliases` (the `import_aliases` field) written with the import
+/// path instead, so `client *redis.Client` reads
+/// `client *github.com/redis/go-redis/v9.Client`. Names from dot imports carry
+/// no selector and stay as written, as does text inside string and rune
+/// literals.
+pub fn qualify_selectors(text: &str, aliases: &Map<String, Value>) -> String {
+    let mut out = String::with_capacity(text.len());
+    let mut chars = text.char_indices().peekable();
+    while let Some((start, c)) = chars.next() {
+        if matches!(c, '"' | '`' | '\'') {
+            let mut end = text.len();
+            let mut escaped = false;
+            for (i, next) in chars.by_ref() {
+                if next == c && !escaped {
+                    end = i + next.len_utf8();
+                    break;
+                }
+                escaped = c != '`' && next == '\\' && !escaped;
+            }
+            out.push_str(&text[start..end]);
+            continue;
+        }
+        if !is_ident_start(c) {
+            out.push(c);
+            continue;
+        }
+        let mut end = start + c.len_utf8();
+        while let Some(&(i, next)) = chars.peek() {
+            if !is_ident_char(next) {
+                break;
+            }
+            end = i + next.len_utf8();
+            chars.next();
+        }
+        let ident = &text[start..end];
+        let after_dot = text[..start].ends_with('.');
+        let selects = text[end..]
+            .strip_prefix('.')
+            .and_then(|rest| rest.chars().next())
+            .is_some_and(is_ident_start);
+        match aliases.get(ident).and_then(Value::as_str) {
+            Some(path) if selects && !after_dot => out.push_str(path),
+            _ => out.push_str(ident),
+        }
+    }
+    out
+}
+
+fn is_ident_start(c: char) -> bool {
+    c == '_' || c.is_alphabetic()
+}
+
+fn is_ident_char(c: char) -> bool {
+    c == '_' || c.is_alphanumeric()
+}
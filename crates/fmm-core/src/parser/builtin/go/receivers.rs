@@ -0,0 +1,164 @@
+use super::directives::is_ignored;
+use serde_json::Value;
+use std::collections::BTreeMap;
+use tree_sitter::Node;
+
+/// Base type name of a `method_declaration` receiver: `Handler` for
+/// `(h *Handler)`, `(Handler)` and `(l *List[T])`.
+pub(crate) fn receiver_type_name(method: Node, source_bytes: &[u8]) -> Option<String> {
+    let receiver = method.child_by_field_name("receiver")?;
+    let mut cursor = receiver.walk();
+    let param = receiver
+        .children(&mut cursor)
+        .find(|child| child.kind() == "parameter_declaration")?;
+    base_type_name(param.child_by_field_name("type")?, source_bytes)
+}
+
+/// Key for a top-level `function_declaration` or `method_declaration`:
+/// `name`, or `Receiver.name` for methods. A file may declare any number of
+/// `init` functions, so each is keyed `init#1`, `init#2`, ... in source
+/// order. None for any other node and for declarations under `//fmm:ignore`.
+pub(super) fn declaration_key(decl: Node, source_bytes: &[u8]) -> Option<String> {
+    if is_ignored(decl, source_bytes) {
+        return None;
+    }
+    let name = decl
+        .child_by_field_name("name")?
+        .utf8_text(source_bytes)
+        .ok()?
+        .to_string();
+    match decl.kind() {
+        "function_declaration" if name == "init" => {
+            Some(format!("init#{}", init_ordinal(decl, source_bytes)))
+        }
+        "function_declaration" => Some(name),
+        "method_declaration" => Some(match receiver_type_name(decl, source_bytes) {
+            Some(receiver) => format!("{receiver}.{name}"),
+            None => name,
+        }),
+        _ => None,
+    }
+}
+
+/// 1-based position of an `init` function among the file's `init` functions,
+/// counting ignored ones so the others keep their keys.
+fn init_ordinal(decl: Node, source_bytes: &[u8]) -> usize {
+    let mut ordinal = 1;
+    let mut prev = decl.prev_sibling();
+    while let Some(node) = prev {
+        if node.kind() == "function_declaration"
+            && node
+                .child_by_field_name("name")
+                .and_then(|name| name.utf8_text(source_bytes).ok())
+                == Some("init")
+        {
+            ordinal += 1;
+        }
+        prev = node.prev_sibling();
+    }
+    ordinal
+}
+
+/// Receiver types whose methods in this file mix pointer and value receivers
+/// or bind the receiver under different names, both of which Go Code Review
+/// Comments asks to keep consistent. Each maps every one of its methods to the
+/// receiver as written (`h *Handler`, `Handler`), so the odd one out is
+/// visible. Types whose methods agree are left out.
+pub(super) fn inconsistent_receivers(
+    root_node: Node,
+    source_bytes: &[u8],
+) -> serde_json::Map<String, Value> {
+    let mut by_type: BTreeMap<String, Vec<(String, ReceiverStyle)>> = BTreeMap::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "method_declaration" || is_ignored(decl, source_bytes) {
+            continue;
+        }
+        let (Some(name), Some(ty), Some(style)) = (
+            decl.child_by_field_name("name")
+                .and_then(|name| name.utf8_text(source_bytes).ok()),
+            receiver_type_name(decl, source_bytes),
+            receiver_style(decl, source_bytes),
+        ) else {
+            continue;
+        };
+        by_type
+            .entry(ty)
+            .or_default()
+            .push((name.to_string(), style));
+    }
+
+    let mut report = serde_json::Map::new();
+    for (ty, methods) in by_type {
+        let first = &methods[0].1;
+        let consistent = methods
+            .iter()
+            .all(|(_, style)| style.pointer == first.pointer && style.name == first.name);
+        if consistent {
+            continue;
+        }
+        let styles = methods
+            .into_iter()
+            .map(|(method, style)| (method, Value::String(style.text)))
+            .collect();
+        report.insert(ty, Value::Object(styles));
+    }
+    report
+}
+
+struct ReceiverStyle {
+    /// Bound name; None when the receiver is unnamed.
+    name: Option<String>,
+    pointer: bool,
+    /// The receiver as written, whitespace collapsed: `h *Handler`.
+    text: String,
+}
+
+fn receiver_style(method: Node, source_bytes: &[u8]) -> Option<ReceiverStyle> {
+    let receiver = method.child_by_field_name("receiver")?;
+    let mut cursor = receiver.walk();
+    let param = receiver
+        .children(&mut cursor)
+        .find(|child| child.kind() == "parameter_declaration")?;
+    let name = param
+        .child_by_field_name("name")
+        .and_then(|name| name.utf8_text(source_bytes).ok())
+        .map(str::to_string);
+    let pointer = is_pointer(param.child_by_field_name("type")?);
+    let text = param
+        .utf8_text(source_bytes)
+        .ok()?
+        .split_whitespace()
+        .collect::<Vec<_>>()
+        .join(" ");
+    Some(ReceiverStyle {
+        name,
+        pointer,
+        text,
+    })
+}
+
+fn is_pointer(ty: Node) -> bool {
+    match ty.kind() {
+        "pointer_type" => true,
+        "parenthesized_type" => {
+            let mut cursor = ty.walk();
+            let inner = ty.named_children(&mut cursor).next();
+            inner.is_some_and(is_pointer)
+        }
+        _ => false,
+    }
+}
+
+fn base_type_name(ty: Node, source_bytes: &[u8]) -> Option<String> {
+    match ty.kind() {
+        "type_identifier" => ty.utf8_text(source_bytes).ok().map(str::to_string),
+        "pointer_type" | "parenthesized_type" => {
+            let mut cursor = ty.walk();
+            let inner = ty.named_children(&mut cursor).next()?;
+            base_type_name(inner, source_bytes)
+        }
+        "generic_type" => base_type_name(ty.child_by_field_name("type")?, source_bytes),
+        _ => None,
+    }
+}
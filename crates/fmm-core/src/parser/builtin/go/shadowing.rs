@@ -0,0 +1,198 @@
+use super::extract_exports::declaration_specs;
+use super::receivers::declaration_key;
+use serde_json::{Value, json};
+use std::collections::{HashMap, HashSet};
+use tree_sitter::Node;
+
+/// Nodes that open a block scope, so a `:=` inside them declares afresh.
+const SCOPE_KINDS: &[&str] = &[
+    "block",
+    "func_literal",
+    "if_statement",
+    "for_statement",
+    "expression_switch_statement",
+    "type_switch_statement",
+    "select_statement",
+    "expression_case",
+    "type_case",
+    "default_case",
+    "communication_case",
+];
+
+/// Types whose parameter and field names bind nothing in the function.
+const TYPE_KINDS: &[&str] = &["function_type", "interface_type", "struct_type"];
+
+/// Local names in functions and methods that shadow a package-level function,
+/// type, const or var declared in this file, in source order. Each records the
+/// enclosing `function` key, the `name`, the `line` of the local declaration
+/// and the `declared_line` of the package-level one. Receivers, type
+/// parameters, parameters, results, `:=`, `var`, `const`, local types, range
+/// and type-switch variables all count; a `:=` that reuses a name already
+/// declared in the same block is not a new declaration.
+///
+/// This is syntax only. Identifiers declared in the package's other files are
+/// unknown, and a receiver or parameter that reuses a package-level name on
+/// purpose (`func (config Config) ...`) is reported like any other shadow.
+pub(super) fn shadowed(root_node: Node, source_bytes: &[u8]) -> Vec<Value> {
+    let package_scope = package_identifiers(root_node, source_bytes);
+    let mut found = Vec::new();
+    if package_scope.is_empty() {
+        return found;
+    }
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        let Some(function) = declaration_key(decl, source_bytes) else {
+            continue;
+        };
+        let mut shadows = Shadows {
+            package_scope: &package_scope,
+            source_bytes,
+            function,
+            scopes: vec![HashSet::new()],
+            found: &mut found,
+        };
+        shadows.walk(decl);
+    }
+    found
+}
+
+/// Names declared at package scope with the line of each declaration.
+fn package_identifiers(root_node: Node, source_bytes: &[u8]) -> HashMap<String, usize> {
+    let mut names = HashMap::new();
+    let mut declare = |name: Node| {
+        if let Ok(text) = name.utf8_text(source_bytes)
+            && text != "_"
+        {
+            names
+                .entry(text.to_string())
+                .or_insert(name.start_position().row + 1);
+        }
+    };
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        match decl.kind() {
+            "function_declaration" => {
+                if let Some(name) = decl.child_by_field_name("name") {
+                    declare(name);
+                }
+            }
+            "type_declaration" | "const_declaration" | "var_declaration" => {
+                let kinds = ["type_spec", "type_alias", "const_spec", "var_spec"];
+                for spec in declaration_specs(decl, &kinds, source_bytes) {
+                    let mut name_cursor = spec.walk();
+                    for name in spec.children_by_field_name("name", &mut name_cursor) {
+                        declare(name);
+                    }
+                }
+            }
+            _ => {}
+        }
+    }
+    names
+}
+
+struct Shadows<'a> {
+    package_scope: &'a HashMap<String, usize>,
+    source_bytes: &'a [u8],
+    function: String,
+    /// Names declared in each enclosing block, innermost last.
+    scopes: Vec<HashSet<String>>,
+    found: &'a mut Vec<Value>,
+}
+
+impl Shadows<'_> {
+    fn walk(&mut self, node: Node) {
+        if TYPE_KINDS.contains(&node.kind()) {
+            return;
+        }
+        let opens_scope = SCOPE_KINDS.contains(&node.kind());
+        if opens_scope {
+            self.scopes.push(HashSet::new());
+        }
+
+        match node.kind() {
+            "parameter_declaration"
+            | "variadic_parameter_declaration"
+            | "type_parameter_declaration"
+            | "var_spec"
+            | "const_spec"
+            | "type_spec"
+            | "type_alias" => {
+                let mut cursor = node.walk();
+                let names: Vec<Node> = node.children_by_field_name("name", &mut cursor).collect();
+                for name in names {
+                    self.declare(name, false);
+                }
+            }
+            "short_var_declaration" => {
+                if let Some(left) = node.child_by_field_name("left") {
+                    self.declare_all(left, true);
+                }
+            }
+            "range_clause" | "receive_statement" if defines(node) => {
+                if let Some(left) = node.child_by_field_name("left") {
+                    self.declare_all(left, true);
+                }
+            }
+            "type_switch_statement" => {
+                if let Some(alias) = node.child_by_field_name("alias") {
+                    self.declare_all(alias, false);
+                }
+            }
+            _ => {}
+        }
+
+        let mut cursor = node.walk();
+        for child in node.children(&mut cursor) {
+            self.walk(child);
+        }
+        if opens_scope {
+            self.scopes.pop();
+        }
+    }
+
+    fn declare_all(&mut self, node: Node, reuses: bool) {
+        if node.kind() == "identifier" {
+            self.declare(node, reuses);
+            return;
+        }
+        let mut cursor = node.walk();
+        let names: Vec<Node> = node.named_children(&mut cursor).collect();
+        for name in names {
+            if name.kind() == "identifier" {
+                self.declare(name, reuses);
+            }
+        }
+    }
+
+    /// Record `name` in the innermost scope. With `reuses`, as for `:=`, a
+    /// name already declared in that scope is an assignment, not a shadow.
+    fn declare(&mut self, name: Node, reuses: bool) {
+        let Ok(text) = name.utf8_text(self.source_bytes) else {
+            return;
+        };
+        let scope = self
+            .scopes
+            .last_mut()
+            .expect("function scope is never popped");
+        let is_new = scope.insert(text.to_string());
+        if reuses && !is_new {
+            return;
+        }
+        if let Some(declared_line) = self.package_scope.get(text) {
+            self.found.push(json!({
+                "function": self.function,
+                "name": text,
+                "line": name.start_position().row + 1,
+                "declared_line": declared_line,
+            }));
+        }
+    }
+}
+
+/// Whether a range clause or select receive declares with `:=` rather than
+/// assigning with `=`.
+fn defines(node: Node) -> bool {
+    let mut cursor = node.walk();
+    node.children(&mut cursor).any(|child| child.kind() == ":=")
+}
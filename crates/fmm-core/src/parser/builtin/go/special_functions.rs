@@ -0,0 +1,76 @@
+use super::receivers::declaration_key;
+use serde_json::Value;
+use tree_sitter::Node;
+
+/// Functions the Go toolchain calls itself, keyed as in the other per-function
+/// fields: `init` for each `init#N`, `main` for `func main()` in package
+/// `main`, and `test_main` for `func TestMain(m *testing.M)`. They are still
+/// ordinary functions everywhere else in the index.
+pub(super) fn special_functions(
+    root_node: Node,
+    source_bytes: &[u8],
+    package: Option<&str>,
+) -> serde_json::Map<String, Value> {
+    let mut special = serde_json::Map::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        if decl.kind() != "function_declaration" {
+            continue;
+        }
+        let Some(key) = declaration_key(decl, source_bytes) else {
+            continue;
+        };
+        let role = if key.starts_with("init#") {
+            "init"
+        } else if key == "main" && package == Some("main") && is_niladic(decl) {
+            "main"
+        } else if key == "TestMain" && takes_testing_m(decl, source_bytes) {
+            "test_main"
+        } else {
+            continue;
+        };
+        special.insert(key, Value::String(role.to_string()));
+    }
+    special
+}
+
+/// No parameters and no results, as `main` must have.
+fn is_niladic(decl: Node) -> bool {
+    decl.child_by_field_name("result").is_none()
+        && decl
+            .child_by_field_name("parameters")
+            .is_some_and(|params| {
+                let mut cursor = params.walk();
+                params
+                    .named_children(&mut cursor)
+                    .all(|param| param.kind() == "comment")
+            })
+}
+
+/// A single `*testing.M` parameter and no results.
+fn takes_testing_m(decl: Node, source_bytes: &[u8]) -> bool {
+    if decl.child_by_field_name("result").is_some() {
+        return false;
+    }
+    let Some(params) = decl.child_by_field_name("parameters") else {
+        return false;
+    };
+    let mut cursor = params.walk();
+    let params: Vec<Node> = params
+        .named_children(&mut cursor)
+        .filter(|param| param.kind() != "comment")
+        .collect();
+    let [param] = params.as_slice() else {
+        return false;
+    };
+    let mut name_cursor = param.walk();
+    param.kind() == "parameter_declaration"
+        && param
+            .children_by_field_name("name", &mut name_cursor)
+            .count()
+            <= 1
+        && param
+            .child_by_field_name("type")
+            .and_then(|ty| ty.utf8_text(source_bytes).ok())
+            .is_some_and(|ty| ty.split_whitespace().collect::<String>() == "*testing.M")
+}
@@ -0,0 +1,81 @@
+use super::directives::is_ignored;
+use tree_sitter::Node;
+
+/// One name bound by a `field_declaration` in a struct body.
+///
+/// `X, Y int` yields two fields sharing the same node. Embedded fields
+/// (`*Base`, `io.Reader`) are named after their type, as the Go spec does.
+pub(crate) struct StructField<'tree> {
+    pub(crate) name: String,
+    pub(crate) node: Node<'tree>,
+    pub(crate) embedded: bool,
+}
+
+/// Fields of a `type_spec` whose type is a struct. Empty for any other spec.
+/// Field declarations under `//fmm:ignore` are left out.
+pub(crate) fn struct_fields<'tree>(
+    spec: Node<'tree>,
+    source_bytes: &[u8],
+) -> Vec<StructField<'tree>> {
+    let mut fields = Vec::new();
+    let Some(body) = spec
+        .child_by_field_name("type")
+        .filter(|ty| ty.kind() == "struct_type")
+        .and_then(|ty| {
+            let mut cursor = ty.walk();
+            ty.children(&mut cursor)
+                .find(|child| child.kind() == "field_declaration_list")
+        })
+    else {
+        return fields;
+    };
+
+    let mut cursor = body.walk();
+    for decl in body.children(&mut cursor) {
+        if decl.kind() != "field_declaration" || is_ignored(decl, source_bytes) {
+            continue;
+        }
+        let mut name_cursor = decl.walk();
+        let names: Vec<String> = decl
+            .children_by_field_name("name", &mut name_cursor)
+            .filter_map(|name| name.utf8_text(source_bytes).ok())
+            .map(str::to_string)
+            .collect();
+        if names.is_empty() {
+            if let Some(name) = decl
+                .child_by_field_name("type")
+                .and_then(|ty| embedded_type_name(ty, source_bytes))
+            {
+                fields.push(StructField {
+                    name,
+                    node: decl,
+                    embedded: true,
+                });
+            }
+            continue;
+        }
+        fields.extend(names.into_iter().map(|name| StructField {
+            name,
+            node: decl,
+            embedded: false,
+        }));
+    }
+    fields
+}
+
+/// Source text of an embedded field without its tag: `*Base`, `io.Reader`.
+pub(super) fn embedded_field_text(decl: Node, source_bytes: &[u8]) -> Option<String> {
+    let ty = decl.child_by_field_name("type")?;
+    let text = std::str::from_utf8(&source_bytes[decl.start_byte()..ty.end_byte()]).ok()?;
+    Some(text.trim().to_string())
+}
+
+/// `Base` for `Base`/`*Base`, `Reader` for `io.Reader`, `List` for `List[T]`.
+fn embedded_type_name(ty: Node, source_bytes: &[u8]) -> Option<String> {
+    let name_node = match ty.kind() {
+        "qualified_type" => ty.child_by_field_name("name")?,
+        "generic_type" => return embedded_type_name(ty.child_by_field_name("type")?, source_bytes),
+        _ => ty,
+    };
+    name_node.utf8_text(source_bytes).ok().map(str::to_string)
+}
@@ -0,0 +1,115 @@
+use super::GoParser;
+use crate::parser::builtin::symbol_metadata as shared_metadata;
+use crate::parser::{DeclarationKind, ExportEntry, SymbolVisibility};
+use tree_sitter::Node;
+
+pub(super) fn go_entry(
+    name: String,
+    node: Node,
+    source_bytes: &[u8],
+    declaration_kind: DeclarationKind,
+) -> ExportEntry {
+    shared_metadata::export_entry(
+        name,
+        node,
+        source_bytes,
+        SymbolVisibility::Public,
+        declaration_kind,
+        signature_end_byte,
+    )
+}
+
+/// Entry for a `type_spec`, `const_spec` or `var_spec`. Specs inside a
+/// parenthesized group carry no keyword of their own, so the signature is
+/// always rebuilt as `<keyword> <spec>` to keep grouped and single forms alike.
+pub(super) fn go_spec_entry(
+    name: String,
+    spec: Node,
+    keyword: &str,
+    source_bytes: &[u8],
+    declaration_kind: DeclarationKind,
+) -> ExportEntry {
+    let mut entry = go_entry(name, spec, source_bytes, declaration_kind);
+    entry.signature = entry.signature.map(|sig| format!("{keyword} {sig}"));
+    entry
+}
+
+/// Struct field member of `parent`. Lowercase field names are package-private,
+/// so they are marked non-exported rather than public.
+pub(crate) fn go_field_entry(
+    name: String,
+    node: Node,
+    source_bytes: &[u8],
+    parent: String,
+) -> ExportEntry {
+    let visibility = visibility_for(&name);
+    shared_metadata::method_entry(
+        name,
+        node,
+        source_bytes,
+        parent,
+        visibility,
+        DeclarationKind::Field,
+        signature_end_byte,
+    )
+}
+
+/// Method member of its receiver type. The signature keeps the receiver, so
+/// pointer and value receivers stay distinguishable.
+pub(crate) fn go_method_entry(
+    name: String,
+    node: Node,
+    source_bytes: &[u8],
+    parent: String,
+) -> ExportEntry {
+    let visibility = visibility_for(&name);
+    shared_metadata::method_entry(
+        name,
+        node,
+        source_bytes,
+        parent,
+        visibility,
+        DeclarationKind::Method,
+        signature_end_byte,
+    )
+}
+
+pub(super) fn visibility_for(name: &str) -> SymbolVisibility {
+    if GoParser::is_exported(name) {
+        SymbolVisibility::Public
+    } else {
+        SymbolVisibility::NonExported
+    }
+}
+
+pub(super) fn type_spec_kind(spec: Node) -> DeclarationKind {
+    match spec.child_by_field_name("type").map(|ty| ty.kind()) {
+        Some("struct_type") => DeclarationKind::Struct,
+        Some("interface_type") => DeclarationKind::Trait,
+        _ => DeclarationKind::Type,
+    }
+}
+
+/// Signatures stop at the body: the function block, the struct field list, or
+/// the opening brace of an interface. Type parameter lists are part of the
+/// header, so `[T ~int | ~string]` and inline interface constraints survive
+/// verbatim.
+fn signature_end_byte(node: Node) -> Option<usize> {
+    if let Some(body) = node.child_by_field_name("body") {
+        return Some(body.start_byte());
+    }
+
+    let ty = node.child_by_field_name("type")?;
+    let mut cursor = ty.walk();
+    match ty.kind() {
+        "struct_type" => ty
+            .children(&mut cursor)
+            .find(|child| child.kind() == "field_declaration_list")
+            .map(|child| child.start_byte()),
+        "interface_type" => ty
+            .children(&mut cursor)
+            .find(|child| child.kind() == "{")
+            .map(|child| child.start_byte()),
+        _ => None,
+    }
+}
@@ -0,0 +1,53 @@
+use tree_sitter::Node;
+
+/// Name prefix, `*testing.X` parameter type and label of each function role
+/// `go test` recognizes. Examples take no parameter.
+const TEST_ROLES: &[(&str, Option<&str>, &str)] = &[
+    ("Test", Some("*testing.T"), "test"),
+    ("Benchmark", Some("*testing.B"), "benchmark"),
+    ("Fuzz", Some("*testing.F"), "fuzz"),
+    ("Example", None, "example"),
+];
+
+/// `test`, `benchmark`, `fuzz` or `example` when a `function_declaration`
+/// has the shape `go test` runs: a role prefix not followed by a lowercase
+/// letter (`TestParse`, `Example_second`, but not `Testify`), the role's
+/// single `*testing.T`/`B`/`F` parameter or none for examples, and no
+/// results. The parameter type must be spelled through the `testing` package.
+pub(super) fn test_kind(decl: Node, source_bytes: &[u8]) -> Option<&'static str> {
+    if decl.kind() != "function_declaration" || decl.child_by_field_name("result").is_some() {
+        return None;
+    }
+    let name = decl
+        .child_by_field_name("name")?
+        .utf8_text(source_bytes)
+        .ok()?;
+    let (_, param_type, kind) = TEST_ROLES.iter().find(|(prefix, _, _)| {
+        name.strip_prefix(prefix)
+            .is_some_and(|rest| !rest.starts_with(|c: char| c.is_lowercase()))
+    })?;
+
+    let params = decl.child_by_field_name("parameters")?;
+    let mut cursor = params.walk();
+    let params: Vec<Node> = params
+        .named_children(&mut cursor)
+        .filter(|param| param.kind() != "comment")
+        .collect();
+    let matches = match (param_type, params.as_slice()) {
+        (None, []) => true,
+        (Some(expected), [param]) => {
+            let mut name_cursor = param.walk();
+            param.kind() == "parameter_declaration"
+                && param
+                    .children_by_field_name("name", &mut name_cursor)
+                    .count()
+                    <= 1
+                && param
+                    .child_by_field_name("type")
+                    .and_then(|ty| ty.utf8_text(source_bytes).ok())
+                    .is_some_and(|ty| ty.split_whitespace().collect::<String>() == *expected)
+        }
+        _ => false,
+    };
+    matches.then_some(*kind)
+}
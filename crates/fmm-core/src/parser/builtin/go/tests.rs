@@ -0,0 +1,28 @@
+mod calls;
+mod complexity;
+mod const_blocks;
+mod const_values;
+mod deprecated;
+mod error_returns;
+mod exports;
+mod expression_list;
+mod go_mod;
+mod ignore_directives;
+mod import_aliases;
+mod import_usage;
+mod imports;
+mod interfaces;
+mod layout;
+mod markers;
+mod methods;
+mod outline_metadata;
+mod package_doc;
+mod qualify;
+mod receivers;
+mod shadowing;
+mod special_functions;
+mod struct_fields;
+mod support;
+mod syntax_errors;
+mod test_functions;
+mod undocumented;
@@ -0,0 +1,80 @@
+use super::support::parse;
+use serde_json::json;
+
+fn calls(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("calls").cloned())
+        .unwrap_or(json!({}))
+}
+
+#[test]
+fn go_calls_resolve_functions_and_receiver_methods() {
+    let source = r#"package server
+
+type Handler struct{}
+
+func (h *Handler) Serve() error {
+    if err := h.validate(); err != nil {
+        return err
+    }
+    go func() { _ = helperFunc() }()
+    return nil
+}
+
+func (h *Handler) validate() error {
+    _ = helperFunc()
+    return nil
+}
+
+func helperFunc() string {
+    return fmt.Sprint("x")
+}
+"#;
+    assert_eq!(
+        calls(source),
+        json!({
+            "Handler.Serve": ["Handler.validate", "helperFunc"],
+            "Handler.validate": ["helperFunc"],
+        })
+    );
+}
+
+#[test]
+fn go_calls_record_recursion_as_self_edge() {
+    let source = r#"package mathx
+
+func Fact(n int) int {
+    if n <= 1 {
+        return 1
+    }
+    return n * Fact(n-1)
+}
+"#;
+    assert_eq!(calls(source), json!({ "Fact": ["Fact"] }));
+}
+
+#[test]
+fn go_calls_skip_interface_and_func_variable_calls() {
+    let source = r#"package server
+
+type Validator interface {
+    validate() error
+}
+
+type Handler struct{}
+
+func (h *Handler) validate() error { return nil }
+
+func helper() {}
+
+func Run(v Validator, h *Handler, cb func()) {
+    v.validate()
+    h.validate()
+    cb()
+    helper := func() {}
+    helper()
+}
+"#;
+    assert_eq!(calls(source), json!({}));
+}
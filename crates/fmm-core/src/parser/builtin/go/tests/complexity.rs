@@ -0,0 +1,81 @@
+use super::support::parse;
+use serde_json::json;
+
+fn complexity(source: &str) -> serde_json::Value {
+    parse(source).custom_fields.unwrap()["complexity"].clone()
+}
+
+#[test]
+fn go_complexity_counts_branches_loops_cases_and_boolean_operators() {
+    let source = r#"
+package main
+
+func Classify(n int, ok bool) string {
+    if n < 0 && ok {
+        return "negative"
+    }
+    for i := range n {
+        _ = i
+    }
+    switch {
+    case n == 0:
+        return "zero"
+    case n > 100 || !ok:
+        return "big"
+    default:
+        return "other"
+    }
+}
+"#;
+    // 1 + if + && + for + 2 cases + ||
+    assert_eq!(complexity(source), json!({ "Classify": 7 }));
+}
+
+#[test]
+fn go_complexity_scores_closures_separately() {
+    let source = r#"
+package main
+
+func (s *Server) Run(items []int) {
+    handle := func(i int) {
+        if i > 0 {
+            go func() {
+                if i > 1 {
+                    return
+                }
+            }()
+        }
+    }
+    for _, i := range items {
+        handle(i)
+    }
+}
+"#;
+    assert_eq!(
+        complexity(source),
+        json!({
+            "Server.Run": 2,
+            "Server.Run.func1": 2,
+            "Server.Run.func1.1": 2,
+        })
+    );
+}
+
+#[test]
+fn go_complexity_type_and_select_cases() {
+    let source = r#"
+package main
+
+func Drain(v any, ch chan int) {
+    switch v.(type) {
+    case int, int64:
+    case string:
+    }
+    select {
+    case <-ch:
+    default:
+    }
+}
+"#;
+    assert_eq!(complexity(source), json!({ "Drain": 4 }));
+}
@@ -0,0 +1,119 @@
+use super::support::parse;
+use crate::parser::ExportEntry;
+
+#[test]
+fn go_implicit_iota_spec_inherits_type_and_value() {
+    let source = r#"
+package main
+
+type Status int
+
+const (
+    StatusActive Status = iota
+    StatusInactive
+    StatusRetired
+)
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert_eq!(
+        signature(exports, "StatusActive"),
+        "const StatusActive Status = iota"
+    );
+    assert_eq!(
+        signature(exports, "StatusInactive"),
+        "const StatusInactive Status = iota"
+    );
+    assert_eq!(
+        signature(exports, "StatusRetired"),
+        "const StatusRetired Status = iota"
+    );
+}
+
+#[test]
+fn go_implicit_spec_inherits_from_latest_explicit_spec() {
+    let source = r#"
+package main
+
+const (
+    KB = 1 << (10 * (iota + 1))
+    MB
+    Label string = "x"
+    Other
+)
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert_eq!(
+        signature(exports, "MB"),
+        "const MB = 1 << (10 * (iota + 1))"
+    );
+    assert_eq!(signature(exports, "Other"), "const Other string = \"x\"");
+}
+
+#[test]
+fn go_implicit_multi_name_spec_keeps_all_names() {
+    let source = r#"
+package main
+
+const (
+    A, B = iota, iota * 10
+    C, D
+)
+"#;
+    let result = parse(source);
+    assert_eq!(
+        signature(&result.metadata.exports, "D"),
+        "const C, D = iota, iota * 10"
+    );
+}
+
+#[test]
+fn go_const_blocks_group_exported_names() {
+    let source = r#"
+package main
+
+const Single = 1
+
+const (
+    StatusActive Status = iota
+    statusHidden
+    StatusInactive
+)
+
+const (
+    internalOnly = 1
+)
+"#;
+    let result = parse(source);
+    let blocks = result
+        .custom_fields
+        .as_ref()
+        .and_then(|fields| fields.get("const_blocks"))
+        .and_then(|value| value.as_array())
+        .expect("const_blocks should be recorded");
+
+    assert_eq!(blocks.len(), 1);
+    assert_eq!(
+        blocks[0]["names"],
+        serde_json::json!(["StatusActive", "StatusInactive"])
+    );
+    assert_eq!(blocks[0]["lines"], serde_json::json!([6, 10]));
+}
+
+#[test]
+fn go_file_without_const_blocks_has_no_const_blocks_field() {
+    let result = parse("package main\n\nconst Single = 1\n");
+    let fields = result.custom_fields.unwrap_or_default();
+    assert!(!fields.contains_key("const_blocks"));
+}
+
+fn signature<'a>(exports: &'a [ExportEntry], name: &str) -> &'a str {
+    exports
+        .iter()
+        .find(|entry| entry.name == name)
+        .and_then(|entry| entry.signature.as_deref())
+        .unwrap_or_else(|| panic!("{name} should carry a signature; exports: {exports:?}"))
+}
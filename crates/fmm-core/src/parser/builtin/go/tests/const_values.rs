@@ -0,0 +1,92 @@
+use super::support::parse;
+use serde_json::json;
+
+fn const_values(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("const_values").cloned())
+        .unwrap_or(json!({}))
+}
+
+#[test]
+fn go_const_values_fold_integer_and_string_expressions() {
+    let source = r#"package size
+
+const (
+    base = 10
+    KB = 1 << base
+    MB = KB * KB
+    Mask = 0xFF &^ 0x0F
+    Greeting = "a" + "b"
+    Path = `C:\tmp`
+)
+"#;
+    assert_eq!(
+        const_values(source),
+        json!({
+            "KB": "1024",
+            "MB": "1048576",
+            "Mask": "240",
+            "Greeting": "\"ab\"",
+            "Path": "\"C:\\\\tmp\"",
+        })
+    );
+}
+
+#[test]
+fn go_const_values_quote_strings_as_go_does() {
+    let source = "package text\n\nconst (\n\tAccent = \"h\u{e9}llo\"\n\tBell = \"\u{1}\"\n\tJoined = \"a\u{200b}b\"\n\tPrivate = \"\u{f0000}\"\n\tLines = `a\tb\nc`\n)\n";
+    assert_eq!(
+        const_values(source),
+        json!({
+            "Accent": "\"h\u{e9}llo\"",
+            "Bell": "\"\\x01\"",
+            "Joined": "\"a\\u200bb\"",
+            "Private": "\"\\U000f0000\"",
+            "Lines": "\"a\\tb\\nc\"",
+        })
+    );
+}
+
+#[test]
+fn go_const_values_repeat_implicit_expressions_with_iota() {
+    let source = r#"package size
+
+type ByteSize int64
+
+const (
+    _ = iota
+    KiB ByteSize = 1 << (10 * iota)
+    MiB
+    GiB
+)
+"#;
+    assert_eq!(
+        const_values(source),
+        json!({ "KiB": "1024", "MiB": "1048576", "GiB": "1073741824" })
+    );
+}
+
+#[test]
+fn go_const_values_fall_back_to_source_text() {
+    let source = r#"package server
+
+const (
+    Timeout = 5 * time.Second
+    Ratio = 1.5
+    Level = Status(2)
+    Escaped = "line\n"
+    Zero = 1 / 0
+)
+"#;
+    assert_eq!(
+        const_values(source),
+        json!({
+            "Timeout": "5 * time.Second",
+            "Ratio": "1.5",
+            "Level": "Status(2)",
+            "Escaped": "\"line\\n\"",
+            "Zero": "1 / 0",
+        })
+    );
+}
@@ -0,0 +1,75 @@
+use super::support::parse;
+use serde_json::json;
+
+fn deprecated(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("deprecated").cloned())
+        .unwrap_or(json!({}))
+}
+
+#[test]
+fn go_deprecated_paragraph_marks_declarations() {
+    let source = r#"package client
+
+// Dial connects to addr.
+//
+// Deprecated: Use DialContext, which
+// honors cancellation.
+func Dial(addr string) {}
+
+type Client struct{}
+
+// Close releases the connection.
+//
+// Deprecated:
+func (c *Client) Close() {}
+
+// Deprecated: no longer used.
+type Options struct{}
+
+// Timeouts for every request.
+//
+// Deprecated: set them on Client.
+const (
+    ReadTimeout = 5
+    WriteTimeout = 5
+)
+
+var (
+    // Deprecated: use ErrClosed.
+    ErrShutdown = errors.New("shutdown")
+    ErrClosed = errors.New("closed")
+)
+"#;
+    assert_eq!(
+        deprecated(source),
+        json!({
+            "Dial": "Use DialContext, which honors cancellation.",
+            "Client.Close": "",
+            "Options": "no longer used.",
+            "ReadTimeout": "set them on Client.",
+            "WriteTimeout": "set them on Client.",
+            "ErrShutdown": "use ErrClosed.",
+        })
+    );
+}
+
+#[test]
+fn go_deprecated_must_begin_a_paragraph() {
+    let source = r#"package client
+
+// Dial connects to addr. Deprecated: is not a marker here,
+// nor is this line.
+// Deprecated: continuing a paragraph either.
+func Dial(addr string) {}
+
+// Deprecated: detached comments are not docs.
+
+func Listen() {}
+
+func Serve() {} // Deprecated: trailing comments are not docs.
+func Close() {}
+"#;
+    assert_eq!(deprecated(source), json!({}));
+}
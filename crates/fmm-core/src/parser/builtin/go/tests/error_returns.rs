@@ -0,0 +1,59 @@
+use super::support::parse;
+use serde_json::json;
+
+fn error_returns(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("error_returns").cloned())
+        .unwrap_or(json!({}))
+}
+
+#[test]
+fn go_error_results_record_position_and_convention() {
+    let source = r#"package store
+
+type Store struct{}
+
+func Open(path string) (*Store, error) { return nil, nil }
+
+func (s *Store) Close() error { return nil }
+
+func (s *Store) lookup(key string) (err error, found bool) { return nil, false }
+
+func Parse(a, b string) (x, y int, err error) { return 0, 0, nil }
+
+func Name() string { return "" }
+"#;
+    assert_eq!(
+        error_returns(source),
+        json!({
+            "Open": {"position": 1, "last": true},
+            "Store.Close": {"position": 0, "last": true},
+            "Store.lookup": {"position": 0, "last": false},
+            "Parse": {"position": 2, "last": true},
+        })
+    );
+}
+
+#[test]
+fn go_only_the_builtin_error_counts() {
+    let source = r#"package store
+
+type ParseError struct{}
+
+func (e *ParseError) Error() string { return "" }
+
+func Parse() *ParseError { return nil }
+
+func Wrap() errors.Error { return nil }
+"#;
+    assert_eq!(error_returns(source), json!({}));
+
+    let shadowed = r#"package store
+
+type error interface{ Code() int }
+
+func Check() error { return nil }
+"#;
+    assert_eq!(error_returns(shadowed), json!({}));
+}
@@ -0,0 +1,133 @@
+use super::support::parse;
+
+#[test]
+fn parse_go_exported_functions() {
+    let source = r#"
+package main
+
+func ExportedFunc() {}
+func unexportedFunc() {}
+"#;
+    let result = parse(source);
+    assert!(
+        result
+            .metadata
+            .export_names()
+            .contains(&"ExportedFunc".to_string())
+    );
+    assert!(
+        !result
+            .metadata
+            .export_names()
+            .contains(&"unexportedFunc".to_string())
+    );
+}
+
+#[test]
+fn parse_go_exported_types() {
+    let source = r#"
+package main
+
+type Config struct {
+    Name string
+}
+
+type handler struct {
+    count int
+}
+
+type Service interface {
+    Start() error
+}
+"#;
+    let result = parse(source);
+    assert!(
+        result
+            .metadata
+            .export_names()
+            .contains(&"Config".to_string())
+    );
+    assert!(
+        result
+            .metadata
+            .export_names()
+            .contains(&"Service".to_string())
+    );
+    assert!(
+        !result
+            .metadata
+            .export_names()
+            .contains(&"handler".to_string())
+    );
+}
+
+#[test]
+fn parse_go_constants_and_vars() {
+    let source = r#"
+package main
+
+const MaxRetries = 3
+const internalLimit = 10
+
+var GlobalState = "init"
+var localVar = "hidden"
+"#;
+    let result = parse(source);
+    assert!(
+        result
+            .metadata
+            .export_names()
+            .contains(&"MaxRetries".to_string())
+    );
+    assert!(
+        result
+            .metadata
+            .export_names()
+            .contains(&"GlobalState".to_string())
+    );
+    assert!(
+        !result
+            .metadata
+            .export_names()
+            .contains(&"internalLimit".to_string())
+    );
+    assert!(
+        !result
+            .metadata
+            .export_names()
+            .contains(&"localVar".to_string())
+    );
+}
+
+#[test]
+fn parse_go_grouped_declarations() {
+    let source = r#"
+package main
+
+const (
+    A, B = 1, 2
+    hidden = 3
+)
+
+var (
+    Exposed = "x"
+)
+
+type (
+    Left int
+    Right = Left
+)
+"#;
+    let result = parse(source);
+    assert_eq!(
+        result.metadata.export_names(),
+        vec!["A", "B", "Exposed", "Left", "Right"]
+    );
+}
+
+#[test]
+fn parse_go_empty() {
+    let result = parse("");
+    assert!(result.metadata.exports.is_empty());
+    assert!(result.metadata.imports.is_empty());
+}
@@ -0,0 +1,18 @@
+use super::super::split_expression_list;
+
+#[test]
+fn go_expression_list_splits_on_top_level_commas_only() {
+    assert_eq!(
+        split_expression_list(r#""a, b", f(1, 2), []int{3, 4}"#),
+        Some(vec![
+            r#""a, b""#.to_string(),
+            "f(1, 2)".to_string(),
+            "[]int{3, 4}".to_string(),
+        ])
+    );
+}
+
+#[test]
+fn go_expression_list_none_for_invalid_source() {
+    assert_eq!(split_expression_list("1, )"), None);
+}
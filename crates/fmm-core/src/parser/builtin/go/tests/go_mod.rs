@@ -0,0 +1,96 @@
+use super::super::GoParser;
+use super::super::go_mod::{extract_module_name, find_go_mod, package_import_path};
+use crate::parser::Parser;
+use std::path::Path;
+
+#[test]
+fn extract_module_name_basic() {
+    let content = "module github.com/example/myproject\n\ngo 1.21\n";
+    assert_eq!(
+        extract_module_name(content),
+        Some("github.com/example/myproject".to_string())
+    );
+}
+
+#[test]
+fn extract_module_name_with_comment() {
+    let content = "// Copyright notice\nmodule github.com/example/proj\n\ngo 1.21\n";
+    assert_eq!(
+        extract_module_name(content),
+        Some("github.com/example/proj".to_string())
+    );
+}
+
+#[test]
+fn extract_module_name_not_found() {
+    assert_eq!(extract_module_name("go 1.21\n"), None);
+    assert_eq!(extract_module_name(""), None);
+}
+
+#[test]
+fn find_go_mod_reads_file() {
+    use std::io::Write;
+    let dir = tempfile::tempdir().unwrap();
+    let go_mod = dir.path().join("go.mod");
+    let mut f = std::fs::File::create(&go_mod).unwrap();
+    writeln!(f, "module github.com/example/myproject").unwrap();
+    writeln!(f).unwrap();
+    writeln!(f, "go 1.21").unwrap();
+    drop(f);
+
+    let source_file = dir.path().join("main.go");
+    let result = find_go_mod(&source_file);
+    assert_eq!(
+        result,
+        Some((dir.path(), "github.com/example/myproject".to_string()))
+    );
+}
+
+#[test]
+fn package_import_path_joins_module_and_relative_dir() {
+    let root = Path::new("/work/mod");
+    assert_eq!(
+        package_import_path(
+            "example.com/mod",
+            root,
+            &root.join("internal/server/server.go")
+        ),
+        Some("example.com/mod/internal/server".to_string())
+    );
+    assert_eq!(
+        package_import_path("example.com/mod", root, &root.join("main.go")),
+        Some("example.com/mod".to_string())
+    );
+}
+
+#[test]
+fn parse_file_records_package_import_path() {
+    let dir = tempfile::tempdir().unwrap();
+    std::fs::write(dir.path().join("go.mod"), "module example.com/mod\n").unwrap();
+    let source_file = dir.path().join("internal/server/server.go");
+
+    let mut parser = GoParser::new().unwrap();
+    let result = parser
+        .parse_file("package server\n\nfunc Run() {}\n", &source_file)
+        .unwrap();
+    assert_eq!(
+        result.custom_fields.unwrap()["import_path"],
+        "example.com/mod/internal/server"
+    );
+}
+
+#[test]
+fn parse_file_without_go_mod_has_no_import_path() {
+    let dir = tempfile::tempdir().unwrap();
+    let source_file = dir.path().join("server/server.go");
+
+    let mut parser = GoParser::new().unwrap();
+    let result = parser
+        .parse_file("package server\n\nfunc Run() {}\n", &source_file)
+        .unwrap();
+    assert!(
+        result
+            .custom_fields
+            .is_none_or(|fields| !fields.contains_key("import_path"))
+    );
+}
@@ -0,0 +1,76 @@
+use super::support::{get_method, parse};
+
+#[test]
+fn go_fmm_ignore_skips_declarations_specs_and_fields() {
+    let source = r#"package server
+
+// Shim is kept for old callers.
+//fmm:ignore deprecated shim
+func Shim() {}
+
+func Serve() {}
+
+//fmm:ignore
+type Legacy struct{}
+
+const (
+    StatusActive = iota
+    //fmm:ignore
+    StatusRetired
+    StatusInactive
+)
+
+type Config struct {
+    Host string
+    //fmm:ignore
+    Secret string
+}
+
+//fmm:ignore
+func (c *Config) Dump() string { return "" }
+
+func (c *Config) Addr() string { return c.Host }
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+    assert_eq!(
+        result.metadata.export_names(),
+        vec!["Serve", "StatusActive", "StatusInactive", "Config"]
+    );
+    assert!(get_method(exports, "Config", "Host").is_some());
+    assert!(get_method(exports, "Config", "Secret").is_none());
+    assert!(get_method(exports, "Config", "Dump").is_none());
+    assert!(get_method(exports, "Config", "Addr").is_some());
+
+    let fields = result.custom_fields.unwrap();
+    assert!(fields["body_lines"].get("Shim").is_none());
+    assert!(fields["body_lines"].get("Config.Dump").is_none());
+}
+
+#[test]
+fn go_fmm_ignore_must_touch_the_declaration() {
+    let source = r#"package server
+
+//fmm:ignore
+
+func Serve() {}
+"#;
+    assert_eq!(parse(source).metadata.export_names(), vec!["Serve"]);
+}
+
+#[test]
+fn go_fmm_ignore_file_drops_the_whole_file() {
+    let source = r#"//fmm:ignore-file
+
+package server
+
+import "fmt"
+
+func Serve() { fmt.Println() }
+"#;
+    let result = parse(source);
+    assert!(result.metadata.exports.is_empty());
+    assert!(result.metadata.imports.is_empty());
+    assert!(result.custom_fields.is_none());
+    assert_eq!(result.metadata.loc, 7);
+}
@@ -0,0 +1,60 @@
+use super::support::parse;
+use serde_json::json;
+
+fn custom_field(source: &str, name: &str) -> Option<serde_json::Value> {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get(name).cloned())
+}
+
+#[test]
+fn go_import_aliases_map_selectors_to_paths() {
+    let source = r#"
+package main
+
+import (
+    "encoding/json"
+    "github.com/redis/go-redis/v9"
+    yaml "gopkg.in/yaml.v3"
+    _ "github.com/lib/pq"
+)
+"#;
+    assert_eq!(
+        custom_field(source, "import_aliases"),
+        Some(json!({
+            "json": "encoding/json",
+            "redis": "github.com/redis/go-redis/v9",
+            "yaml": "gopkg.in/yaml.v3",
+        }))
+    );
+    assert_eq!(custom_field(source, "dot_imports"), None);
+}
+
+#[test]
+fn go_import_aliases_assumed_name_cuts_at_non_identifier() {
+    let source = "package main\n\nimport \"gopkg.in/yaml.v3\"\n";
+    assert_eq!(
+        custom_field(source, "import_aliases"),
+        Some(json!({ "yaml": "gopkg.in/yaml.v3" }))
+    );
+}
+
+#[test]
+fn go_dot_imports_listed_separately() {
+    let source = r#"
+package main_test
+
+import (
+    . "github.com/onsi/gomega"
+    "testing"
+)
+"#;
+    assert_eq!(
+        custom_field(source, "dot_imports"),
+        Some(json!(["github.com/onsi/gomega"]))
+    );
+    assert_eq!(
+        custom_field(source, "import_aliases"),
+        Some(json!({ "testing": "testing" }))
+    );
+}
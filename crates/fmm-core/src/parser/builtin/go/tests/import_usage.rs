@@ -0,0 +1,64 @@
+use super::support::{parse, parse_with_module};
+use serde_json::json;
+
+fn field(result: crate::parser::ParseResult, name: &str) -> serde_json::Value {
+    result
+        .custom_fields
+        .and_then(|fields| fields.get(name).cloned())
+        .unwrap_or(serde_json::Value::Null)
+}
+
+const SOURCE: &str = r#"package api
+
+import (
+    "context"
+    "net/http"
+
+    "github.com/myorg/proj/internal/db"
+    "github.com/myorg/proj/internal/cache"
+    log "github.com/sirupsen/logrus"
+    yaml "gopkg.in/yaml.v3"
+    _ "github.com/lib/pq"
+    . "github.com/onsi/gomega"
+)
+
+func Serve(ctx context.Context, store *db.Store) http.Handler {
+    log.Info("serving")
+    return nil
+}
+"#;
+
+#[test]
+fn go_import_kinds_separate_stdlib_third_party_and_module() {
+    let result = parse_with_module(SOURCE, "github.com/myorg/proj");
+    assert_eq!(
+        field(result, "import_kinds"),
+        json!({
+            "context": "stdlib",
+            "net/http": "stdlib",
+            "github.com/myorg/proj/internal/db": "module",
+            "github.com/myorg/proj/internal/cache": "module",
+            "github.com/sirupsen/logrus": "third_party",
+            "gopkg.in/yaml.v3": "third_party",
+            "github.com/lib/pq": "third_party",
+            "github.com/onsi/gomega": "third_party",
+        })
+    );
+}
+
+#[test]
+fn go_unused_imports_skip_blank_and_dot_imports() {
+    assert_eq!(
+        field(parse(SOURCE), "unused_imports"),
+        json!(["github.com/myorg/proj/internal/cache", "gopkg.in/yaml.v3"])
+    );
+}
+
+#[test]
+fn go_file_using_every_import_has_no_unused_imports() {
+    let source = "package api\n\nimport \"fmt\"\n\nfunc Hello() { fmt.Println() }\n";
+    assert_eq!(
+        field(parse(source), "unused_imports"),
+        serde_json::Value::Null
+    );
+}
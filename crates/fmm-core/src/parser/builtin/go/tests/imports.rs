@@ -0,0 +1,141 @@
+use super::support::{parse, parse_with_module};
+
+#[test]
+fn parse_go_imports() {
+    let source = r#"
+package main
+
+import (
+    "fmt"
+    "os"
+    "net/http"
+    "github.com/gin-gonic/gin"
+)
+"#;
+    let result = parse(source);
+    assert!(result.metadata.imports.contains(&"fmt".to_string()));
+    assert!(result.metadata.imports.contains(&"os".to_string()));
+    assert!(result.metadata.imports.contains(&"net/http".to_string()));
+    assert!(
+        result
+            .metadata
+            .dependencies
+            .contains(&"github.com/gin-gonic/gin".to_string())
+    );
+}
+
+#[test]
+fn same_module_import_classified_as_dependency() {
+    let source = r#"
+package handler
+
+import "github.com/example/proj/internal/handler"
+"#;
+    let result = parse_with_module(source, "github.com/example/proj");
+    assert!(
+        result
+            .metadata
+            .dependencies
+            .contains(&"internal/handler".to_string()),
+        "same-module import should be dependency, got: {:?}",
+        result.metadata.dependencies
+    );
+    assert!(
+        result.metadata.imports.is_empty(),
+        "imports should be empty, got: {:?}",
+        result.metadata.imports
+    );
+}
+
+#[test]
+fn third_party_import_classified_as_import() {
+    let source = r#"
+package main
+
+import "github.com/gin-gonic/gin"
+"#;
+    let result = parse_with_module(source, "github.com/example/proj");
+    assert!(
+        result
+            .metadata
+            .imports
+            .contains(&"github.com/gin-gonic/gin".to_string()),
+        "third-party import should be in imports, got: {:?}",
+        result.metadata.imports
+    );
+    assert!(
+        result.metadata.dependencies.is_empty(),
+        "dependencies should be empty, got: {:?}",
+        result.metadata.dependencies
+    );
+}
+
+#[test]
+fn stdlib_import_classified_as_import_with_module_name() {
+    let source = r#"
+package main
+
+import (
+    "fmt"
+    "net/http"
+)
+"#;
+    let result = parse_with_module(source, "github.com/example/proj");
+    assert!(
+        result.metadata.imports.contains(&"fmt".to_string()),
+        "fmt should be in imports"
+    );
+    assert!(
+        result.metadata.imports.contains(&"net/http".to_string()),
+        "net/http should be in imports"
+    );
+    assert!(result.metadata.dependencies.is_empty());
+}
+
+#[test]
+fn mixed_imports_with_module_name() {
+    let source = r#"
+package main
+
+import (
+    "fmt"
+    "github.com/example/proj/internal/config"
+    "github.com/example/proj/pkg/utils"
+    "github.com/gin-gonic/gin"
+    "golang.org/x/net/context"
+)
+"#;
+    let result = parse_with_module(source, "github.com/example/proj");
+    assert!(
+        result
+            .metadata
+            .dependencies
+            .contains(&"internal/config".to_string()),
+        "internal/config should be a dependency"
+    );
+    assert!(
+        result
+            .metadata
+            .dependencies
+            .contains(&"pkg/utils".to_string()),
+        "pkg/utils should be a dependency"
+    );
+    assert!(
+        result.metadata.imports.contains(&"fmt".to_string()),
+        "fmt should be in imports"
+    );
+    assert!(
+        result
+            .metadata
+            .imports
+            .contains(&"github.com/gin-gonic/gin".to_string()),
+        "gin should be in imports"
+    );
+    assert!(
+        result
+            .metadata
+            .imports
+            .contains(&"golang.org/x/net/context".to_string()),
+        "golang.org/x/net/context should be in imports"
+    );
+}
@@ -0,0 +1,63 @@
+use super::support::{get_method, parse};
+use crate::parser::{DeclarationKind, SymbolVisibility};
+
+#[test]
+fn go_interface_methods_nest_under_interface() {
+    let source = r#"
+package storage
+
+type Store interface {
+    Get(ctx context.Context, key string) (string, error)
+    Delete(key string) error
+    flush()
+}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    let get = get_method(exports, "Store", "Get").expect("Store.Get member");
+    assert_eq!(get.declaration_kind, Some(DeclarationKind::Method));
+    assert_eq!(get.visibility, Some(SymbolVisibility::Public));
+    assert_eq!(
+        get.signature.as_deref(),
+        Some("Get(ctx context.Context, key string) (string, error)")
+    );
+    assert_eq!((get.start_line, get.end_line), (5, 5));
+    assert!(get_method(exports, "Store", "Delete").is_some());
+    assert!(
+        get_method(exports, "Store", "flush").is_none(),
+        "unexported interface methods stay out of the index"
+    );
+    assert_eq!(result.metadata.export_names(), vec!["Store"]);
+}
+
+#[test]
+fn go_embedded_interfaces_recorded_not_expanded() {
+    let source = r#"
+package storage
+
+type ReadCloser interface {
+    io.Reader
+    Closer
+    Close() error
+}
+
+type Number interface {
+    ~int | ~float64
+}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert!(get_method(exports, "ReadCloser", "Close").is_some());
+    assert!(get_method(exports, "ReadCloser", "Read").is_none());
+    let embedded = result
+        .custom_fields
+        .as_ref()
+        .and_then(|fields| fields.get("embedded_interfaces"))
+        .expect("embedded_interfaces should be recorded");
+    assert_eq!(
+        embedded,
+        &serde_json::json!({ "ReadCloser": ["io.Reader", "Closer"] })
+    );
+}
@@ -0,0 +1,106 @@
+use super::support::parse;
+use serde_json::json;
+
+fn layouts(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("struct_layout").cloned())
+        .unwrap_or(json!({}))
+}
+
+// Expected sizes assume a 64-bit host.
+
+#[test]
+fn go_struct_layout_reports_offsets_and_trailing_padding() {
+    let source = r#"package server
+
+type Config struct {
+    Host  string
+    Port  int
+    Debug bool
+}
+"#;
+    assert_eq!(
+        layouts(source)["Config"],
+        json!({
+            "size": 32,
+            "align": 8,
+            "fields": [
+                {"name": "Host", "offset": 0, "size": 16},
+                {"name": "Port", "offset": 16, "size": 8},
+                {"name": "Debug", "offset": 24, "size": 1, "padding": 7},
+            ],
+        })
+    );
+}
+
+#[test]
+fn go_struct_layout_suggests_a_smaller_field_order() {
+    let source = r#"package server
+
+type Flags struct {
+    A bool
+    B int64
+    C bool
+}
+"#;
+    let flags = &layouts(source)["Flags"];
+    assert_eq!(flags["size"], 24);
+    assert_eq!(flags["fields"][0]["padding"], 7);
+    assert_eq!(flags["suggested_order"], json!(["B", "A", "C"]));
+    assert_eq!(flags["suggested_size"], 16);
+}
+
+#[test]
+fn go_struct_layout_resolves_types_declared_in_the_file() {
+    let source = r#"package shapes
+
+type Status uint8
+
+type Point struct {
+    X, Y int32
+}
+
+type Shape struct {
+    Origin Point
+    Tags   [2]string
+    Next   *Shape
+    Kind   Status
+}
+"#;
+    let result = layouts(source);
+    assert_eq!(result["Point"]["size"], 8);
+    assert_eq!(result["Point"]["align"], 4);
+    assert_eq!(
+        result["Shape"]["fields"],
+        json!([
+            {"name": "Origin", "offset": 0, "size": 8},
+            {"name": "Tags", "offset": 8, "size": 32},
+            {"name": "Next", "offset": 40, "size": 8},
+            {"name": "Kind", "offset": 48, "size": 1, "padding": 7},
+        ])
+    );
+    assert!(result["Shape"].get("suggested_order").is_none());
+}
+
+#[test]
+fn go_struct_layout_marks_imported_field_types_unknown() {
+    let source = r#"package client
+
+type Client struct {
+    conn    net.Conn
+    timeout time.Duration
+    retries int
+}
+
+type Box[T any] struct {
+    value T
+}
+"#;
+    assert_eq!(
+        layouts(source),
+        json!({
+            "Client": {"size": "unknown", "unknown_fields": ["conn", "timeout"]},
+        })
+    );
+}
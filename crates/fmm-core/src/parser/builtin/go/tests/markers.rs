@@ -0,0 +1,69 @@
+use super::super::markers_with_tags;
+use super::support::parse;
+use serde_json::json;
+
+fn markers(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("markers").cloned())
+        .unwrap_or(json!([]))
+}
+
+#[test]
+fn go_markers_attach_to_enclosing_function() {
+    let source = r#"package server
+
+func Process() {
+    // TODO: stream the response
+    data := 1
+    /* FIXME(alice): handle
+       HACK around the proxy */
+    _ = data
+}
+
+func (h *Handler) validate() error {
+    return nil // XXX is not a default tag
+}
+"#;
+    assert_eq!(
+        markers(source),
+        json!([
+            { "tag": "TODO", "line": 4, "text": "stream the response", "function": "Process" },
+            { "tag": "FIXME", "line": 6, "text": "(alice): handle", "function": "Process" },
+            { "tag": "HACK", "line": 7, "text": "around the proxy", "function": "Process" },
+        ])
+    );
+}
+
+#[test]
+fn go_markers_between_functions_are_package_scope() {
+    let source = r#"package server
+
+// TODO: split this file
+func Run() {}
+
+// NOTODO and TODOS are not markers
+"#;
+    assert_eq!(
+        markers(source),
+        json!([{ "tag": "TODO", "line": 3, "text": "split this file" }])
+    );
+}
+
+#[test]
+fn go_markers_with_tags_adds_custom_tags_to_the_defaults() {
+    let source = r#"package server
+
+func (h *Handler) validate() error {
+    // TODO: check the host
+    return nil // XXX: always valid
+}
+"#;
+    assert_eq!(
+        serde_json::Value::Array(markers_with_tags(source, &["XXX".to_string()])),
+        json!([
+            { "tag": "TODO", "line": 4, "text": "check the host", "function": "Handler.validate" },
+            { "tag": "XXX", "line": 5, "text": "always valid", "function": "Handler.validate" },
+        ])
+    );
+}
@@ -0,0 +1,120 @@
+use super::support::{get_method, parse};
+use crate::parser::{DeclarationKind, SymbolVisibility};
+
+#[test]
+fn go_exported_methods_nest_under_receiver_type() {
+    let source = r#"
+package server
+
+func (h *Handler) Close() error { return nil }
+
+type Handler struct{}
+
+func NewHandler() *Handler { return &Handler{} }
+
+func (h Handler) Name() string { return "h" }
+
+func (h *Handler) validate() error { return nil }
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    let close = get_method(exports, "Handler", "Close").expect("Close should nest under Handler");
+    assert_eq!(close.declaration_kind, Some(DeclarationKind::Method));
+    assert_eq!(close.visibility, Some(SymbolVisibility::Public));
+    assert_eq!(
+        close.signature.as_deref(),
+        Some("func (h *Handler) Close() error")
+    );
+
+    let name = get_method(exports, "Handler", "Name").expect("Name should nest under Handler");
+    assert_eq!(
+        name.signature.as_deref(),
+        Some("func (h Handler) Name() string")
+    );
+
+    assert!(get_method(exports, "Handler", "validate").is_none());
+    assert_eq!(
+        result.metadata.export_names(),
+        vec!["Handler", "NewHandler"]
+    );
+}
+
+#[test]
+fn go_generic_receiver_resolves_base_type() {
+    let source = r#"
+package list
+
+type List[T any] struct{}
+
+func (l *List[T]) Push(v T) {}
+"#;
+    let result = parse(source);
+    assert!(get_method(&result.metadata.exports, "List", "Push").is_some());
+}
+
+#[test]
+fn go_method_on_type_declared_elsewhere_stays_top_level() {
+    let source = r#"
+package server
+
+type Alias = external.Client
+
+func (c *Client) Retry() {}
+func (a Alias) Ping() {}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    let retry = exports
+        .iter()
+        .find(|e| e.name == "Retry")
+        .expect("Retry should be indexed");
+    assert!(retry.parent_class.is_none());
+    assert_eq!(retry.declaration_kind, Some(DeclarationKind::Method));
+    assert_eq!(retry.signature.as_deref(), Some("func (c *Client) Retry()"));
+    assert!(get_method(exports, "Alias", "Ping").is_some());
+
+    let external = result
+        .custom_fields
+        .as_ref()
+        .and_then(|fields| fields.get("external_receivers"))
+        .expect("external_receivers should be recorded");
+    assert_eq!(external, &serde_json::json!(["Client.Retry"]));
+}
+
+#[test]
+fn go_exported_methods_on_unexported_types_are_not_indexed() {
+    let source = r#"
+package server
+
+type handler struct{}
+
+func (h *handler) ServeHTTP() {}
+"#;
+    let result = parse(source);
+    assert!(result.metadata.exports.is_empty());
+}
+
+#[test]
+fn go_body_lines_zero_for_bodyless_and_one_line_functions() {
+    let source = r#"
+package runtime
+
+//go:linkname nanotime runtime.nanotime
+func nanotime() int64
+
+func Zero() {}
+
+func (s *Stack) Push(v int) {
+    s.items = append(s.items, v)
+}
+"#;
+    let result = parse(source);
+    let body_lines = result.custom_fields.unwrap()["body_lines"].clone();
+
+    assert_eq!(
+        body_lines,
+        serde_json::json!({ "nanotime": 0, "Zero": 0, "Stack.Push": 1 })
+    );
+}
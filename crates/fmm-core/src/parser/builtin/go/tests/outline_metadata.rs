@@ -0,0 +1,238 @@
+use super::support::{get_method, parse};
+use crate::parser::{DeclarationKind, ExportEntry, SymbolVisibility};
+
+#[test]
+fn go_declarations_carry_outline_metadata() {
+    let source = r#"
+package main
+
+func Run() {}
+
+type Config struct {
+    Name string
+}
+
+type Service interface {
+    Start() error
+}
+
+type Status int
+
+const MaxRetries = 3
+
+var Default = Config{}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert_entry(exports, "Run", DeclarationKind::Fn);
+    assert_entry(exports, "Config", DeclarationKind::Struct);
+    assert_entry(exports, "Service", DeclarationKind::Trait);
+    assert_entry(exports, "Status", DeclarationKind::Type);
+    assert_entry(exports, "MaxRetries", DeclarationKind::Const);
+    assert_entry(exports, "Default", DeclarationKind::Var);
+}
+
+#[test]
+fn go_signature_is_declaration_header_text() {
+    let source = r#"
+package main
+
+// NewHandler doc text is outside the declaration node.
+func NewHandler(cfg Config) *Handler {
+    return &Handler{config: cfg}
+}
+
+type Config struct {
+    Host string
+}
+
+type Service interface {
+    Start() error
+}
+
+const (
+    MaxRetries = 3
+)
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert_signature(
+        exports,
+        "NewHandler",
+        "func NewHandler(cfg Config) *Handler",
+    );
+    assert_signature(exports, "Config", "type Config struct");
+    assert_signature(exports, "Service", "type Service interface");
+    assert_signature(exports, "MaxRetries", "const MaxRetries = 3");
+}
+
+#[test]
+fn go_generic_function_keeps_type_parameters() {
+    let source = r#"
+package main
+
+func Map[T any, U any](in []T, f func(T) U) []U {
+    return nil
+}
+"#;
+    let result = parse(source);
+    assert_signature(
+        &result.metadata.exports,
+        "Map",
+        "func Map[T any, U any](in []T, f func(T) U) []U",
+    );
+}
+
+#[test]
+fn go_generic_union_constraint_is_preserved() {
+    let source = r#"
+package main
+
+func Sum[T ~int | ~string](values ...T) T {
+    var zero T
+    return zero
+}
+"#;
+    let result = parse(source);
+    assert_signature(
+        &result.metadata.exports,
+        "Sum",
+        "func Sum[T ~int | ~string](values ...T) T",
+    );
+}
+
+#[test]
+fn go_generic_inline_interface_constraint_is_preserved() {
+    let source = r#"
+package main
+
+func Join[S interface{ ~[]E }, E fmt.Stringer](items S) string {
+    return ""
+}
+"#;
+    let result = parse(source);
+    assert_signature(
+        &result.metadata.exports,
+        "Join",
+        "func Join[S interface{ ~[]E }, E fmt.Stringer](items S) string",
+    );
+}
+
+#[test]
+fn go_type_parameter_shadowing_package_type_keeps_source_name() {
+    let source = r#"
+package main
+
+type Config struct{}
+
+func Load[Config any](raw []byte) Config {
+    var c Config
+    return c
+}
+"#;
+    let result = parse(source);
+    assert_signature(
+        &result.metadata.exports,
+        "Load",
+        "func Load[Config any](raw []byte) Config",
+    );
+}
+
+#[test]
+fn go_generic_type_specs_keep_type_parameters() {
+    let source = r#"
+package main
+
+type List[T any] struct {
+    items []T
+}
+
+type Set[K comparable] map[K]struct{}
+
+type Number interface {
+    ~int | ~int64 | ~float64
+}
+
+type (
+    Pair[K comparable, V any] struct {
+        Key K
+        Val V
+    }
+)
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert_signature(exports, "List", "type List[T any] struct");
+    assert_signature(exports, "Set", "type Set[K comparable] map[K]struct{}");
+    assert_signature(exports, "Number", "type Number interface");
+    assert_signature(exports, "Pair", "type Pair[K comparable, V any] struct");
+}
+
+#[test]
+fn go_signature_keeps_parameter_and_result_lists_verbatim() {
+    let source = r#"
+package main
+
+func Printf(prefix string, args ...any) (n int, err error) {
+    return 0, nil
+}
+
+func Close() error {
+    return nil
+}
+
+func Discard(_ string, _ int) {}
+
+func Add(a, b int) int {
+    return a + b
+}
+
+func Split(s string) (string, string) {
+    return s, s
+}
+
+func (h *Handler) Read(p []byte) (n int, err error) {
+    return 0, nil
+}
+
+type Handler struct{}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert_signature(
+        exports,
+        "Printf",
+        "func Printf(prefix string, args ...any) (n int, err error)",
+    );
+    assert_signature(exports, "Close", "func Close() error");
+    assert_signature(exports, "Discard", "func Discard(_ string, _ int)");
+    assert_signature(exports, "Add", "func Add(a, b int) int");
+    assert_signature(exports, "Split", "func Split(s string) (string, string)");
+    let read = get_method(exports, "Handler", "Read").expect("Handler.Read should be indexed");
+    assert_eq!(
+        read.signature.as_deref(),
+        Some("func (h *Handler) Read(p []byte) (n int, err error)")
+    );
+}
+
+fn assert_entry(exports: &[ExportEntry], name: &str, kind: DeclarationKind) {
+    let entry = exports
+        .iter()
+        .find(|entry| entry.name == name && entry.parent_class.is_none())
+        .unwrap_or_else(|| panic!("{name} should be indexed; exports: {exports:?}"));
+    assert_eq!(entry.visibility, Some(SymbolVisibility::Public));
+    assert_eq!(entry.declaration_kind, Some(kind));
+    assert!(entry.signature.is_some(), "{name} should carry signature");
+}
+
+fn assert_signature(exports: &[ExportEntry], name: &str, signature: &str) {
+    let entry = exports
+        .iter()
+        .find(|entry| entry.name == name && entry.parent_class.is_none())
+        .unwrap_or_else(|| panic!("{name} should be indexed; exports: {exports:?}"));
+    assert_eq!(entry.signature.as_deref(), Some(signature));
+}
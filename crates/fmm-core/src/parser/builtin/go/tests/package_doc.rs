@@ -0,0 +1,71 @@
+use super::support::parse;
+use serde_json::json;
+
+fn package_doc(source: &str) -> Option<serde_json::Value> {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("package_doc").cloned())
+}
+
+#[test]
+fn go_package_doc_joins_line_comments() {
+    let source = r#"// Package server handles incoming requests.
+//
+// It wraps net/http.
+package server
+"#;
+    assert_eq!(
+        package_doc(source),
+        Some(json!(
+            "Package server handles incoming requests.\n\nIt wraps net/http."
+        ))
+    );
+}
+
+#[test]
+fn go_package_doc_strips_block_comment_markers() {
+    let source = "/*\nPackage server handles incoming requests.\n*/\npackage server\n";
+    assert_eq!(
+        package_doc(source),
+        Some(json!("Package server handles incoming requests."))
+    );
+}
+
+#[test]
+fn go_package_doc_ignores_detached_comments() {
+    let source = r#"//go:build linux
+
+// Copyright 2024 The Authors.
+
+package server
+"#;
+    assert_eq!(package_doc(source), None);
+}
+
+#[test]
+fn go_package_doc_keeps_only_adjacent_group() {
+    let source = r#"// Copyright 2024 The Authors.
+
+// Package server handles incoming requests.
+package server
+"#;
+    assert_eq!(
+        package_doc(source),
+        Some(json!("Package server handles incoming requests."))
+    );
+}
+
+#[test]
+fn go_build_constraint_recorded_from_header() {
+    let source =
+        "//go:build linux && !arm64\n\n// Package server handles requests.\npackage server\n";
+    let fields = parse(source).custom_fields.unwrap();
+    assert_eq!(fields["build_constraint"], json!("linux && !arm64"));
+}
+
+#[test]
+fn go_build_constraint_ignores_comments_after_package_clause() {
+    let source = "package server\n\n//go:build linux\nfunc Run() {}\n";
+    let fields = parse(source).custom_fields.unwrap_or_default();
+    assert!(!fields.contains_key("build_constraint"));
+}
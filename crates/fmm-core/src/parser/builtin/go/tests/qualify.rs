@@ -0,0 +1,50 @@
+use super::super::qualify_selectors;
+use serde_json::json;
+
+fn aliases() -> serde_json::Map<String, serde_json::Value> {
+    json!({
+        "redis": "github.com/redis/go-redis/v9",
+        "http": "net/http",
+    })
+    .as_object()
+    .unwrap()
+    .clone()
+}
+
+#[test]
+fn go_qualify_selectors_expands_imported_packages() {
+    assert_eq!(
+        qualify_selectors("client *redis.Client", &aliases()),
+        "client *github.com/redis/go-redis/v9.Client"
+    );
+    assert_eq!(
+        qualify_selectors(
+            "func Serve(w http.ResponseWriter, pool []*redis.Client) (map[string]http.Handler, error)",
+            &aliases()
+        ),
+        "func Serve(w net/http.ResponseWriter, pool []*github.com/redis/go-redis/v9.Client) (map[string]net/http.Handler, error)"
+    );
+}
+
+#[test]
+fn go_qualify_selectors_leaves_other_names_and_literals() {
+    // `cfg` is no import, `Matcher` comes from a dot import, and the string
+    // only looks like a selector.
+    assert_eq!(
+        qualify_selectors(
+            r#"func Check(cfg Config, m Matcher) string // "redis.Nil""#,
+            &aliases()
+        ),
+        r#"func Check(cfg Config, m Matcher) string // "redis.Nil""#
+    );
+    assert_eq!(
+        qualify_selectors(r#"const Key = "redis.key""#, &aliases()),
+        r#"const Key = "redis.key""#
+    );
+    assert_eq!(
+        qualify_selectors("var x = cfg.redis.Client", &aliases()),
+        "var x = cfg.redis.Client"
+    );
+    // Package name alone, with no selected name.
+    assert_eq!(qualify_selectors("redis", &aliases()), "redis");
+}
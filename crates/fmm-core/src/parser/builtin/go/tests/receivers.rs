@@ -0,0 +1,60 @@
+use super::support::parse;
+use serde_json::json;
+
+fn inconsistent_receivers(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("inconsistent_receivers").cloned())
+        .unwrap_or(json!({}))
+}
+
+#[test]
+fn go_consistent_receivers_are_not_reported() {
+    let source = r#"package server
+
+type Handler struct{}
+
+func (h *Handler) Process() {}
+
+func (h *Handler) validate() bool { return true }
+"#;
+    assert_eq!(inconsistent_receivers(source), json!({}));
+}
+
+#[test]
+fn go_mixed_pointer_and_value_receivers_are_reported() {
+    let source = r#"package server
+
+type Handler struct{}
+
+func (h *Handler) Process() {}
+
+func (h Handler) String() string { return "" }
+"#;
+    assert_eq!(
+        inconsistent_receivers(source),
+        json!({"Handler": {"Process": "h *Handler", "String": "h Handler"}})
+    );
+}
+
+#[test]
+fn go_differing_receiver_names_are_reported() {
+    let source = r#"package store
+
+type List[T any] struct{}
+
+func (l *List[T]) Push(v T) {}
+
+func (list *List[T]) Len() int { return 0 }
+
+func (*List[T]) reset() {}
+"#;
+    assert_eq!(
+        inconsistent_receivers(source),
+        json!({"List": {
+            "Push": "l *List[T]",
+            "Len": "list *List[T]",
+            "reset": "*List[T]",
+        }})
+    );
+}
@@ -0,0 +1,89 @@
+use super::support::parse;
+use serde_json::json;
+
+fn shadowed(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("shadowed").cloned())
+        .unwrap_or(json!([]))
+}
+
+#[test]
+fn go_locals_shadowing_package_names_are_reported() {
+    let source = r#"package server
+
+var config = load()
+
+type Handler struct{}
+
+func load() string { return "" }
+
+func (h *Handler) Serve(load func() string) {
+	config := load()
+	for _, Handler := range []int{1} {
+		_ = Handler
+	}
+	_ = config
+}
+"#;
+    assert_eq!(
+        shadowed(source),
+        json!([
+            {"function": "Handler.Serve", "name": "load", "line": 9, "declared_line": 7},
+            {"function": "Handler.Serve", "name": "config", "line": 10, "declared_line": 3},
+            {"function": "Handler.Serve", "name": "Handler", "line": 11, "declared_line": 5},
+        ])
+    );
+}
+
+#[test]
+fn go_reused_short_declarations_and_func_type_params_are_not_shadows() {
+    let source = r#"package server
+
+var err error
+
+func handle(cb func(err error)) {
+	n, err2 := 1, error(nil)
+	m, err2 := 2, error(nil)
+	_, _, _ = n, m, err2
+}
+
+func retry() {
+	err := try()
+	if err != nil {
+		return
+	}
+	x, err := 1, try()
+	_ = x
+}
+
+func try() error { return nil }
+"#;
+    assert_eq!(
+        shadowed(source),
+        json!([
+            {"function": "retry", "name": "err", "line": 12, "declared_line": 3},
+        ])
+    );
+}
+
+#[test]
+fn go_scoped_redeclarations_are_each_reported() {
+    let source = r#"package server
+
+const limit = 10
+
+func run[limit any](v limit) {
+	if limit := 3; limit > 0 {
+		_ = limit
+	}
+}
+"#;
+    assert_eq!(
+        shadowed(source),
+        json!([
+            {"function": "run", "name": "limit", "line": 5, "declared_line": 3},
+            {"function": "run", "name": "limit", "line": 6, "declared_line": 3},
+        ])
+    );
+}
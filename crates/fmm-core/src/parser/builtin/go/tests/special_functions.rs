@@ -0,0 +1,46 @@
+use super::support::parse;
+use serde_json::json;
+
+#[test]
+fn go_init_functions_get_ordinal_keys() {
+    let source = r#"package main
+
+func init() { setup() }
+
+func main() {}
+
+func init() {
+	if debug {
+		trace()
+	}
+}
+
+func setup() {}
+"#;
+    let fields = parse(source).custom_fields.expect("custom fields");
+    assert_eq!(
+        fields["special_functions"],
+        json!({"init#1": "init", "main": "main", "init#2": "init"})
+    );
+    // Per-function fields keep one entry per init instead of overwriting.
+    assert_eq!(fields["complexity"]["init#1"], 1);
+    assert_eq!(fields["complexity"]["init#2"], 2);
+    assert_eq!(fields["calls"], json!({"init#1": ["setup"]}));
+}
+
+#[test]
+fn go_test_main_and_library_main_are_told_apart() {
+    let source = r#"package server_test
+
+import "testing"
+
+func TestMain(m *testing.M) {}
+
+func main() {}
+"#;
+    let fields = parse(source).custom_fields.expect("custom fields");
+    assert_eq!(
+        fields["special_functions"],
+        json!({"TestMain": "test_main"})
+    );
+}
@@ -0,0 +1,99 @@
+use super::support::{get_method, parse};
+use crate::parser::{DeclarationKind, SymbolVisibility};
+
+#[test]
+fn go_exported_struct_fields_become_members() {
+    let source = r#"
+package server
+
+type Config struct {
+    Host  string `json:"host"`
+    Port  int
+    Debug bool
+    token string
+}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    let host = get_method(exports, "Config", "Host").expect("Host should be a member");
+    assert_eq!(host.declaration_kind, Some(DeclarationKind::Field));
+    assert_eq!(host.visibility, Some(SymbolVisibility::Public));
+    assert_eq!(
+        host.signature.as_deref(),
+        Some("Host  string `json:\"host\"`")
+    );
+
+    let port = get_method(exports, "Config", "Port").expect("Port should be a member");
+    assert_eq!(port.signature.as_deref(), Some("Port  int"));
+    assert!(get_method(exports, "Config", "Debug").is_some());
+    assert!(
+        get_method(exports, "Config", "token").is_none(),
+        "unexported fields are served by the private member extractor"
+    );
+    assert_eq!(result.metadata.export_names(), vec!["Config"]);
+}
+
+#[test]
+fn go_multi_name_fields_are_indexed_individually() {
+    let source = r#"
+package geo
+
+type Point struct {
+    X, Y float64
+}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+    let x = get_method(exports, "Point", "X").expect("X should be a member");
+    let y = get_method(exports, "Point", "Y").expect("Y should be a member");
+    assert_eq!(x.signature.as_deref(), Some("X, Y float64"));
+    assert_eq!(y.start_line, x.start_line);
+}
+
+#[test]
+fn go_embedded_fields_are_labelled_separately() {
+    let source = r#"
+package server
+
+type Server struct {
+    *Base
+    io.Reader
+    sync.Mutex
+    Name string
+}
+
+type internal struct {
+    Base
+}
+"#;
+    let result = parse(source);
+    let exports = &result.metadata.exports;
+
+    assert!(get_method(exports, "Server", "Base").is_some());
+    assert!(get_method(exports, "Server", "Reader").is_some());
+    assert!(get_method(exports, "Server", "Mutex").is_some());
+
+    let embedded = result
+        .custom_fields
+        .as_ref()
+        .and_then(|fields| fields.get("embedded_fields"))
+        .expect("embedded_fields should be recorded");
+    assert_eq!(
+        embedded,
+        &serde_json::json!({"Server": ["*Base", "io.Reader", "sync.Mutex"]})
+    );
+}
+
+#[test]
+fn go_fields_of_unexported_structs_are_not_indexed() {
+    let source = r#"
+package server
+
+type privateState struct {
+    Counter int
+}
+"#;
+    let result = parse(source);
+    assert!(result.metadata.exports.is_empty());
+}
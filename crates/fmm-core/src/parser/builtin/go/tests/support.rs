@@ -0,0 +1,25 @@
+use super::super::GoParser;
+use crate::parser::{ExportEntry, ParseResult, Parser};
+
+pub(super) fn parse(source: &str) -> ParseResult {
+    let mut parser = GoParser::new().unwrap();
+    parser.parse(source).unwrap()
+}
+
+// --- ALP-796 / ALP-797: go.mod-aware import classification ---
+
+pub(super) fn parse_with_module(source: &str, module: &str) -> ParseResult {
+    let mut parser = GoParser::new().unwrap();
+    parser.module_name = Some(module.to_string());
+    parser.parse(source).unwrap()
+}
+
+pub(super) fn get_method<'a>(
+    exports: &'a [ExportEntry],
+    class: &str,
+    method: &str,
+) -> Option<&'a ExportEntry> {
+    exports
+        .iter()
+        .find(|e| e.parent_class.as_deref() == Some(class) && e.name == method)
+}
@@ -0,0 +1,24 @@
+use super::super::GoParser;
+use crate::parser::Parser;
+
+#[test]
+fn go_syntax_error_none_for_valid_source() {
+    let mut parser = GoParser::new().unwrap();
+    let source = "package main\n\nfunc Run() error {\n    return nil\n}\n";
+    assert_eq!(parser.syntax_error(source), None);
+}
+
+#[test]
+fn go_syntax_error_reports_position_relative_to_source() {
+    let mut parser = GoParser::new().unwrap();
+    let source = "package main\n\nfunc Run() {\n    x := )\n}\n";
+    let error = parser.syntax_error(source).expect("unbalanced paren");
+    assert!(error.starts_with("4:"), "got: {error}");
+}
+
+#[test]
+fn go_syntax_error_reports_missing_closing_brace() {
+    let mut parser = GoParser::new().unwrap();
+    let source = "package main\n\nfunc Run() {\n    return\n";
+    assert!(parser.syntax_error(source).is_some());
+}
@@ -0,0 +1,81 @@
+use super::support::parse;
+use crate::parser::DeclarationKind;
+use serde_json::json;
+
+fn field(source: &str, name: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get(name).cloned())
+        .unwrap_or(serde_json::Value::Null)
+}
+
+#[test]
+fn go_test_functions_are_labeled_by_role() {
+    let source = r#"package parser_test
+
+import "testing"
+
+func TestParse(t *testing.T) {}
+
+func Test_edgeCase(t *testing.T) {}
+
+func BenchmarkParse(b *testing.B) {}
+
+func FuzzParse(f *testing.F) {}
+
+func ExampleParse() {}
+
+func Example_second() {}
+
+func Testify(t *testing.T) {}
+
+func TestHelper(t *testing.B) {}
+
+func ExampleWithArgs(x int) {}
+
+func TestResult(t *testing.T) error { return nil }
+"#;
+    assert_eq!(
+        field(source, "test_kind"),
+        json!({
+            "TestParse": "test",
+            "Test_edgeCase": "test",
+            "BenchmarkParse": "benchmark",
+            "FuzzParse": "fuzz",
+            "ExampleParse": "example",
+            "Example_second": "example",
+        })
+    );
+}
+
+#[test]
+fn go_test_functions_use_the_test_declaration_kind() {
+    let source = r#"package parser
+
+import "testing"
+
+func TestParse(t *testing.T) {}
+
+func Parse() {}
+"#;
+    let result = parse(source);
+    let kind = |name: &str| {
+        result
+            .metadata
+            .exports
+            .iter()
+            .find(|entry| entry.name == name)
+            .and_then(|entry| entry.declaration_kind)
+    };
+    assert_eq!(kind("TestParse"), Some(DeclarationKind::Test));
+    assert_eq!(kind("Parse"), Some(DeclarationKind::Fn));
+}
+
+#[test]
+fn go_package_name_keeps_external_test_packages_apart() {
+    assert_eq!(field("package parser\n", "package"), json!("parser"));
+    assert_eq!(
+        field("package parser_test\n", "package"),
+        json!("parser_test")
+    );
+}
@@ -0,0 +1,61 @@
+use super::support::parse;
+use serde_json::json;
+
+fn undocumented(source: &str) -> serde_json::Value {
+    parse(source)
+        .custom_fields
+        .and_then(|fields| fields.get("undocumented").cloned())
+        .unwrap_or(json!([]))
+}
+
+#[test]
+fn go_undocumented_lists_exported_declarations_without_doc_comments() {
+    let source = r#"package server
+
+// Server is documented.
+type Server struct{}
+
+type Client struct{}
+
+func (s *Server) Start() {}
+
+// Stop is documented.
+func (s *Server) Stop() {}
+
+func (s *Server) reset() {}
+
+type state struct{}
+
+func (s state) Exported() {}
+
+var Default = Server{}
+
+// Dial is documented.
+
+func Dial() {}
+"#;
+    assert_eq!(
+        undocumented(source),
+        json!(["Client", "Server.Start", "Default", "Dial"])
+    );
+}
+
+#[test]
+fn go_undocumented_accepts_spec_or_group_doc_comments() {
+    let source = r#"package server
+
+// Limits for every request.
+const (
+    MaxBody = 1 << 20
+    MaxHeaders = 64
+)
+
+var (
+    // ErrClosed is returned after Close.
+    ErrClosed = errors.New("closed")
+    ErrBusy = errors.New("busy") // trailing comments are not docs
+    ErrLimit = errors.New("limit")
+)
+"#;
+    assert_eq!(undocumented(source), json!(["ErrBusy", "ErrLimit"]));
+}
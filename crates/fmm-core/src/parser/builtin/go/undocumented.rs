@@ -0,0 +1,69 @@
+use super::GoParser;
+use super::extract_exports::{declaration_specs, declared_type_names, spec_names};
+use super::receivers::{declaration_key, receiver_type_name};
+use crate::parser::builtin::query_helpers::extract_field_text;
+use serde_json::Value;
+use tree_sitter::Node;
+
+/// Exported functions, methods, types, consts and vars with no doc comment,
+/// in source order. Methods are `Receiver.name`. As in `go doc`, a spec in a
+/// parenthesized group is documented by its own comment or by the group's.
+pub(super) fn undocumented(root_node: Node, source_bytes: &[u8]) -> Vec<Value> {
+    let declared_types = declared_type_names(root_node, source_bytes);
+    let mut names = Vec::new();
+    let mut cursor = root_node.walk();
+    for decl in root_node.children(&mut cursor) {
+        match decl.kind() {
+            "function_declaration" | "method_declaration" => {
+                let Some(name) = extract_field_text(&decl, source_bytes, "name") else {
+                    continue;
+                };
+                let hidden_receiver = receiver_type_name(decl, source_bytes)
+                    .is_some_and(|ty| declared_types.contains(&ty) && !GoParser::is_exported(&ty));
+                if GoParser::is_exported(&name)
+                    && !hidden_receiver
+                    && !has_doc_comment(decl)
+                    && let Some(key) = declaration_key(decl, source_bytes)
+                {
+                    names.push(Value::String(key));
+                }
+            }
+            "type_declaration" | "const_declaration" | "var_declaration" => {
+                let group_doc = has_doc_comment(decl);
+                let kinds = ["type_spec", "type_alias", "const_spec", "var_spec"];
+                for spec in declaration_specs(decl, &kinds, source_bytes) {
+                    if group_doc || has_doc_comment(spec) {
+                        continue;
+                    }
+                    let spec_names = if spec.kind().starts_with("type_") {
+                        extract_field_text(&spec, source_bytes, "name")
+                            .into_iter()
+                            .collect()
+                    } else {
+                        spec_names(spec, source_bytes)
+                    };
+                    names.extend(
+                        spec_names
+                            .into_iter()
+                            .filter(|name| GoParser::is_exported(name))
+                            .map(Value::String),
+                    );
+                }
+            }
+            _ => {}
+        }
+    }
+    names
+}
+
+/// Whether a comment ends on the line directly above `node`. A trailing
+/// comment on the previous declaration's line belongs to that declaration.
+fn has_doc_comment(node: Node) -> bool {
+    node.prev_sibling().is_some_and(|prev| {
+        prev.kind() == "comment"
+            && prev.end_position().row + 1 == node.start_position().row
+            && prev
+                .prev_sibling()
+                .is_none_or(|before| before.end_position().row < prev.start_position().row)
+    })
+}
@@ -5,7 +5,7 @@ use streaming_iterator::StreamingIterator;
 use tree_sitter::{Language, Parser as TSParser, Query, QueryCursor};
 
 use super::query_helpers::{
-    collect_matches, collect_matches_with_lines, compile_query, make_parser,
+    collect_matches, collect_matches_with_lines, compile_query, first_syntax_error, make_parser,
 };
 
 pub struct JavaParser {
@@ -231,6 +231,10 @@ impl Parser for JavaParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "java"
     }
@@ -3,7 +3,7 @@ use anyhow::Result;
 use std::collections::HashSet;
 use tree_sitter::{Language, Parser as TSParser};
 
-use super::query_helpers::make_parser;
+use super::query_helpers::{first_syntax_error, make_parser};
 
 pub struct LuaParser {
     parser: TSParser,
@@ -206,6 +206,10 @@ impl Parser for LuaParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "lua"
     }
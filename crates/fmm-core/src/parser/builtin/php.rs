@@ -1,4 +1,6 @@
-use super::query_helpers::{collect_matches_with_lines, compile_query, make_parser};
+use super::query_helpers::{
+    collect_matches_with_lines, compile_query, first_syntax_error, make_parser,
+};
 use crate::parser::{ExportEntry, Metadata, ParseResult, Parser};
 use anyhow::Result;
 use std::collections::HashSet;
@@ -302,6 +304,10 @@ impl Parser for PhpParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "php"
     }
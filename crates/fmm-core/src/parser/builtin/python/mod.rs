@@ -4,7 +4,9 @@ mod symbol_metadata;
 #[cfg(test)]
 mod tests;
 
-use super::query_helpers::{collect_matches, compile_query, make_parser, top_level_ancestor};
+use super::query_helpers::{
+    collect_matches, compile_query, first_syntax_error, make_parser, top_level_ancestor,
+};
 use crate::parser::{ExportEntry, Metadata, ParseResult, Parser};
 use anyhow::Result;
 use std::collections::{HashMap, HashSet};
@@ -607,6 +609,10 @@ impl Parser for PythonParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "python"
     }
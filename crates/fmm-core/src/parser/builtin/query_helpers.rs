@@ -46,6 +46,55 @@ pub fn make_parser(language: &Language, lang_name: &str) -> Result<TSParser> {
     Ok(parser)
 }
 
+/// First syntax error in `source` as `line:column: message`, 1-based and
+/// relative to `source`. None when the source parses cleanly.
+///
+/// Backs every parser's [`Parser::syntax_error`](crate::parser::Parser::syntax_error):
+/// tree-sitter marks the error and missing nodes it recovered around, and the
+/// earliest one is reported.
+pub fn first_syntax_error(parser: &mut TSParser, source: &str) -> Option<String> {
+    let tree = parser.parse(source, None)?;
+    let root_node = tree.root_node();
+    if !root_node.has_error() {
+        return None;
+    }
+
+    let node = first_error_node(root_node).unwrap_or(root_node);
+    let position = node.start_position();
+    let message = if node.is_missing() {
+        format!("missing `{}`", node.kind())
+    } else {
+        let text = node
+            .utf8_text(source.as_bytes())
+            .ok()
+            .and_then(|text| text.lines().next())
+            .map(str::trim)
+            .unwrap_or_default();
+        if text.is_empty() {
+            "syntax error".to_string()
+        } else {
+            format!("syntax error near `{text}`")
+        }
+    };
+    Some(format!(
+        "{}:{}: {message}",
+        position.row + 1,
+        position.column + 1
+    ))
+}
+
+/// Depth-first, so the reported position is the earliest one in the source.
+fn first_error_node(node: tree_sitter::Node) -> Option<tree_sitter::Node> {
+    if node.is_error() || node.is_missing() {
+        return Some(node);
+    }
+    if !node.has_error() {
+        return None;
+    }
+    let mut cursor = node.walk();
+    node.children(&mut cursor).find_map(first_error_node)
+}
+
 /// Compile a single tree-sitter [`Query`] with a descriptive error on failure.
 ///
 /// Replaces the repeated two-line `Query::new(...).map_err(...)` pattern in
@@ -366,4 +415,21 @@ mod tests {
         let results = collect_named_matches(&query, "name", tree.root_node(), source.as_bytes());
         assert_eq!(results, vec!["a", "z"]);
     }
+
+    #[test]
+    fn first_syntax_error_none_for_valid_source() {
+        let (_, mut parser) = setup_ts();
+        assert_eq!(
+            first_syntax_error(&mut parser, "export function foo() {}\n"),
+            None
+        );
+    }
+
+    #[test]
+    fn first_syntax_error_reports_earliest_position() {
+        let (_, mut parser) = setup_ts();
+        let source = "export function foo() {}\nconst x = );\nconst y = );\n";
+        let error = first_syntax_error(&mut parser, source).expect("unbalanced paren");
+        assert!(error.starts_with("2:"), "got: {error}");
+    }
 }
@@ -1,6 +1,6 @@
 use super::query_helpers::{
-    collect_matches_with_lines, collect_named_matches, compile_query, make_parser,
-    top_level_ancestor,
+    collect_matches_with_lines, collect_named_matches, compile_query, first_syntax_error,
+    make_parser, top_level_ancestor,
 };
 use crate::parser::{ExportEntry, Metadata, ParseResult, Parser};
 use anyhow::Result;
@@ -199,6 +199,10 @@ impl Parser for RubyParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "ruby"
     }
@@ -8,7 +8,7 @@ pub(crate) use symbol_metadata::rust_method_entry;
 #[cfg(test)]
 mod tests;
 
-use super::query_helpers::{compile_query, make_parser};
+use super::query_helpers::{compile_query, first_syntax_error, make_parser};
 use crate::parser::{Metadata, ParseResult, Parser};
 use anyhow::Result;
 use std::collections::{HashMap, HashSet};
@@ -289,6 +289,10 @@ impl Parser for RustParser {
         self.parse_inner(source, is_binary_entry_point(file_path))
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "rust"
     }
@@ -3,7 +3,9 @@ use anyhow::Result;
 use std::collections::{HashMap, HashSet};
 use tree_sitter::{Language, Parser as TSParser};
 
-use super::query_helpers::{extract_child_text, has_modifier, make_parser, push_export};
+use super::query_helpers::{
+    extract_child_text, first_syntax_error, has_modifier, make_parser, push_export,
+};
 
 pub struct SwiftParser {
     parser: TSParser,
@@ -297,6 +299,10 @@ impl Parser for SwiftParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         "swift"
     }
@@ -15,6 +15,8 @@ use anyhow::Result;
 use std::collections::HashSet;
 use tree_sitter::{Language, Parser as TSParser};
 
+use super::query_helpers::first_syntax_error;
+
 // If using tree-sitter queries (recommended for most languages), also add:
 // use super::query_helpers::{collect_matches_with_lines, compile_query, make_parser};
 // use tree_sitter::Query;
@@ -156,6 +158,11 @@ impl Parser for TemplateParser {
         })
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        // Every tree-sitter parser reports errors the same way; keep as is.
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         // The identifier written into sidecar frontmatter (lowercase, no spaces).
         // Examples: "haskell", "erlang", "cobol"
@@ -13,7 +13,7 @@ use anyhow::Result;
 use streaming_iterator::StreamingIterator;
 use tree_sitter::{Language, Parser as TSParser, Query, QueryCursor};
 
-use super::query_helpers::{compile_query, make_parser, top_level_ancestor};
+use super::query_helpers::{compile_query, first_syntax_error, make_parser, top_level_ancestor};
 
 use tsconfig::load_tsconfig_paths;
 
@@ -287,6 +287,10 @@ impl Parser for TypeScriptParser {
         self.parse_with_aliases(source, &aliases)
     }
 
+    fn syntax_error(&mut self, source: &str) -> Option<String> {
+        first_syntax_error(&mut self.parser, source)
+    }
+
     fn language_id(&self) -> &'static str {
         if self.is_tsx { "tsx" } else { "typescript" }
     }
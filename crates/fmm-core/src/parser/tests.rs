@@ -262,3 +262,15 @@ fn descriptor_extensions_match_parser_extensions() {
 
 // NOTE: Cross-module guard test `default_languages_matches_registry` lives in
 // fmm-cli's config/mod.rs tests. It requires Config which is not in fmm-core.
+
+#[test]
+fn declaration_kind_all_matches_serialized_names() {
+    let names: HashSet<&str> = DeclarationKind::ALL.iter().map(|k| k.as_str()).collect();
+    assert_eq!(names.len(), DeclarationKind::ALL.len());
+    for kind in DeclarationKind::ALL {
+        assert_eq!(
+            serde_json::to_value(kind).unwrap(),
+            serde_json::json!(kind.as_str())
+        );
+    }
+}
@@ -97,6 +97,7 @@ pub enum DeclarationKind {
     Method,
     Field,
     Const,
+    Var,
     Test,
     Struct,
     Trait,
@@ -109,12 +110,31 @@ pub enum DeclarationKind {
 }
 
 impl DeclarationKind {
+    /// Every kind, in declaration order; the values `fmm exports --kind` accepts.
+    pub const ALL: [Self; 14] = [
+        Self::Fn,
+        Self::Method,
+        Self::Field,
+        Self::Const,
+        Self::Var,
+        Self::Test,
+        Self::Struct,
+        Self::Trait,
+        Self::Impl,
+        Self::Enum,
+        Self::Variant,
+        Self::Module,
+        Self::Macro,
+        Self::Type,
+    ];
+
     pub fn as_str(self) -> &'static str {
         match self {
             Self::Fn => "fn",
             Self::Method => "method",
             Self::Field => "field",
             Self::Const => "const",
+            Self::Var => "var",
             Self::Test => "test",
             Self::Struct => "struct",
             Self::Trait => "trait",
@@ -243,6 +263,17 @@ pub trait Parser: Send + Sync {
         self.parse(source)
     }
 
+    /// First syntax error in `source` as `line:column: message`, or None when
+    /// it parses cleanly.
+    ///
+    /// [`parse`](Parser::parse) tolerates broken files and keeps whatever the
+    /// grammar could recover; callers that must not emit a partial result check
+    /// this first. The default reports none, for parsers that do not track
+    /// syntax errors; the tree-sitter parsers override it.
+    fn syntax_error(&mut self, _source: &str) -> Option<String> {
+        None
+    }
+
     /// The language identifier used in frontmatter sections (e.g., "rust", "python").
     fn language_id(&self) -> &'static str;
 
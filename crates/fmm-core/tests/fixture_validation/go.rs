@@ -22,6 +22,18 @@ fn validate_go_fixture() {
     ];
     assert_eq!(result.metadata.export_names(), expected_exports);
 
+    // Implicit iota specs inherit the type and value of the previous spec
+    let status_inactive = result
+        .metadata
+        .exports
+        .iter()
+        .find(|e| e.name == "StatusInactive")
+        .unwrap();
+    assert_eq!(
+        status_inactive.signature.as_deref(),
+        Some("const StatusInactive Status = iota")
+    );
+
     // Imports: stdlib packages
     assert!(
         result
@@ -68,3 +80,69 @@ fn validate_go_fixture() {
 
     assert!(result.metadata.loc > 50);
 }
+
+#[test]
+fn validate_go_fixture_function_metrics() {
+    let source = include_str!(concat!(
+        env!("CARGO_MANIFEST_DIR"),
+        "/../../fixtures/sample.go"
+    ));
+
+    let result = parse_fixture(GoParser::new().unwrap(), source);
+    let fields = result.custom_fields.expect("Go fixture has custom fields");
+    let body_lines = &fields["body_lines"];
+
+    assert_eq!(body_lines["Process"], 2);
+    assert_eq!(body_lines["Handler.validate"], 4);
+    assert_eq!(body_lines["helperFunc"], 1);
+
+    let complexity = &fields["complexity"];
+    assert_eq!(complexity["Handler.validate"], 2);
+    assert_eq!(complexity["Process"], 1);
+
+    // No function in the fixture calls another one declared in it.
+    assert!(!fields.contains_key("calls"));
+
+    assert_eq!(
+        fields["const_values"],
+        serde_json::json!({ "MaxRetries": "3", "StatusActive": "0", "StatusInactive": "1" })
+    );
+
+    // MaxRetries, Status and Config carry doc comments; the iota group does not.
+    assert_eq!(
+        fields["undocumented"],
+        serde_json::json!(["StatusActive", "StatusInactive"])
+    );
+
+    // No go.mod reaches a bare parse, so nothing is classified as in-module.
+    assert_eq!(
+        fields["import_kinds"],
+        serde_json::json!({
+            "encoding/json": "stdlib",
+            "fmt": "stdlib",
+            "net/http": "stdlib",
+            "github.com/gin-gonic/gin": "third_party",
+            "github.com/redis/go-redis/v9": "third_party",
+        })
+    );
+    // Config is string, int, bool: seven bytes of padding follow Debug on a
+    // 64-bit host.
+    let config_layout = &fields["struct_layout"]["Config"];
+    assert_eq!(config_layout["fields"][2]["name"], "Debug");
+    if cfg!(target_pointer_width = "64") {
+        assert_eq!(config_layout["size"], 32);
+        assert_eq!(config_layout["fields"][2]["padding"], 7);
+    }
+
+    // The fixture imports gin without referencing it.
+    assert_eq!(
+        fields["unused_imports"],
+        serde_json::json!(["github.com/gin-gonic/gin"])
+    );
+
+    // validate ends in the conventional error; NewHandler returns none.
+    assert_eq!(
+        fields["error_returns"],
+        serde_json::json!({ "Handler.validate": { "position": 0, "last": true } })
+    );
+}
@@ -23,6 +23,10 @@ fn template_rs_is_structurally_complete() {
         src.contains("fn language_id"),
         "template.rs must implement language_id()"
     );
+    assert!(
+        src.contains("fn syntax_error"),
+        "template.rs must implement syntax_error()"
+    );
     assert!(
         src.contains("fn extensions"),
         "template.rs must implement extensions()"
@@ -27,6 +27,14 @@ fn go_real_repo_http_handler() {
             .export_names()
             .contains(&"NewHandler".to_string())
     );
+    // ServeHTTP nests under its receiver type instead of the top level
+    assert!(
+        result
+            .metadata
+            .exports
+            .iter()
+            .any(|e| e.name == "ServeHTTP" && e.parent_class.as_deref() == Some("Handler"))
+    );
     // healthCheck is unexported (lowercase)
     assert!(
         !result
@@ -69,6 +77,14 @@ fn go_real_repo_interface_pattern() {
             .export_names()
             .contains(&"NewPostgresStore".to_string())
     );
+    // Interface methods nest under the interface
+    assert!(
+        result
+            .metadata
+            .exports
+            .iter()
+            .any(|e| e.name == "Delete" && e.parent_class.as_deref() == Some("Store"))
+    );
     assert!(
         !result
             .metadata